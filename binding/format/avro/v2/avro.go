@@ -0,0 +1,489 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/linkedin/goavro/v2"
+
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	ApplicationCloudEventsAvro = "application/cloudevents+avro"
+
+	specversion = "specversion"
+	// dataRecord is the name of the record representing JSON values in the schema.
+	dataRecord = "io.cloudevents.CloudEventData"
+)
+
+// Schema is the Avro schema of the CloudEvents Avro format.
+const Schema = `{
+  "namespace": "io.cloudevents",
+  "type": "record",
+  "name": "CloudEvent",
+  "version": "1.0",
+  "doc": "Avro Event Format for CloudEvents",
+  "fields": [
+    {
+      "name": "attribute",
+      "type": {
+        "type": "map",
+        "values": ["null", "boolean", "int", "string", "bytes"]
+      }
+    },
+    {
+      "name": "data",
+      "type": [
+        "bytes",
+        "null",
+        "boolean",
+        {
+          "type": "map",
+          "values": [
+            "null",
+            "boolean",
+            {
+              "type": "record",
+              "name": "CloudEventData",
+              "doc": "Representation of a JSON Value",
+              "fields": [
+                {
+                  "name": "value",
+                  "type": {
+                    "type": "map",
+                    "values": [
+                      "null",
+                      "boolean",
+                      {"type": "map", "values": "CloudEventData"},
+                      {"type": "array", "items": "CloudEventData"},
+                      "double",
+                      "string"
+                    ]
+                  }
+                }
+              ]
+            },
+            "double",
+            "string"
+          ]
+        },
+        {"type": "array", "items": "CloudEventData"},
+        "double",
+        "string"
+      ]
+    }
+  ]
+}`
+
+var (
+	// Avro is the built-in "application/cloudevents+avro" format.
+	Avro = avroFmt{}
+
+	codec = mustCodec(Schema)
+)
+
+// StringOfApplicationCloudEventsAvro returns a string pointer to
+// "application/cloudevents+avro"
+func StringOfApplicationCloudEventsAvro() *string {
+	a := ApplicationCloudEventsAvro
+	return &a
+}
+
+func init() {
+	format.Add(Avro)
+}
+
+func mustCodec(schema string) *goavro.Codec {
+	c, err := goavro.NewCodec(schema)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+type avroFmt struct{}
+
+func (avroFmt) MediaType() string {
+	return ApplicationCloudEventsAvro
+}
+
+func (avroFmt) Marshal(e *event.Event) ([]byte, error) {
+	native, err := ToAvro(e)
+	if err != nil {
+		return nil, err
+	}
+	return codec.BinaryFromNative(nil, native)
+}
+
+func (avroFmt) Unmarshal(b []byte, e *event.Event) error {
+	native, _, err := codec.NativeFromBinary(b)
+	if err != nil {
+		return err
+	}
+	e2, err := FromAvro(native)
+	if err != nil {
+		return err
+	}
+	*e = *e2
+	return nil
+}
+
+// ToAvro converts an SDK event to the native goavro representation of the event,
+// which can be encoded with the Schema.
+// All the attributes are stored in the attribute map: the attributes of type URI,
+// URI-reference and Timestamp are stored as strings, as the Avro format has no type for them.
+// Binary data is stored as bytes. JSON data is stored as a JSON value of the CloudEventData
+// union when the schema can represent it, e.g. an object or an array of objects, any other
+// data as string.
+func ToAvro(e *event.Event) (map[string]interface{}, error) {
+	version := spec.VS.Version(e.SpecVersion())
+	if version == nil {
+		return nil, fmt.Errorf("unsupported spec version %q", e.SpecVersion())
+	}
+	attributes := make(map[string]interface{})
+	for _, a := range version.Attributes() {
+		if v := a.Get(e.Context); v != nil {
+			attr, err := attributeFor(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode attribute %s: %w", a.Name(), err)
+			}
+			attributes[a.Name()] = attr
+		}
+	}
+	for name, value := range e.Extensions() {
+		attr, err := attributeFor(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode attribute %s: %w", name, err)
+		}
+		attributes[name] = attr
+	}
+
+	var data interface{}
+	switch {
+	case e.DataEncoded == nil:
+	case e.DataBase64:
+		data = goavro.Union("bytes", e.DataEncoded)
+	default:
+		data = goavro.Union("string", string(e.DataEncoded))
+		if isJSON(e.DataContentType()) {
+			if value, ok := decodeJSON(e.DataEncoded); ok {
+				if union, ok := dataFor(value); ok {
+					data = union
+				}
+			}
+		}
+	}
+	return map[string]interface{}{
+		"attribute": attributes,
+		"data":      data,
+	}, nil
+}
+
+func attributeFor(v interface{}) (interface{}, error) {
+	vv, err := types.Validate(v)
+	if err != nil {
+		return nil, err
+	}
+	switch vt := vv.(type) {
+	case bool:
+		return goavro.Union("boolean", vt), nil
+	case int32:
+		return goavro.Union("int", vt), nil
+	case []byte:
+		return goavro.Union("bytes", vt), nil
+	case string:
+		return goavro.Union("string", vt), nil
+	case types.URI, types.URIRef, types.Timestamp:
+		s, err := types.Format(vt)
+		if err != nil {
+			return nil, err
+		}
+		return goavro.Union("string", s), nil
+	default:
+		return nil, fmt.Errorf("unsupported attribute type: %T", v)
+	}
+}
+
+// isJSON returns whether the content type is a JSON media type.
+func isJSON(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == event.ApplicationJSON || mediaType == event.TextJSON || strings.HasSuffix(mediaType, "+json")
+}
+
+// decodeJSON decodes a JSON value, keeping its numbers as json.Number.
+func decodeJSON(b []byte) (interface{}, bool) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return nil, false
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, false
+	}
+	return value, true
+}
+
+// doubleFor returns the double of a JSON number, if it reads back as the same number.
+func doubleFor(n json.Number) (float64, bool) {
+	f, err := n.Float64()
+	if err != nil {
+		return 0, false
+	}
+	want, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return 0, false
+	}
+	got, ok := new(big.Rat).SetString(strconv.FormatFloat(f, 'g', -1, 64))
+	return f, ok && want.Cmp(got) == 0
+}
+
+// dataFor returns the union of the data storing the JSON value, if the schema can represent it.
+// The JSON strings and null are not stored as JSON values, they are kept as JSON text,
+// as well as the values with numbers which would lose precision as a double.
+func dataFor(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case bool:
+		return goavro.Union("boolean", v), true
+	case json.Number:
+		f, ok := doubleFor(v)
+		if !ok {
+			return nil, false
+		}
+		return goavro.Union("double", f), true
+	case map[string]interface{}:
+		unions := make(map[string]interface{}, len(v))
+		for k, member := range v {
+			var union interface{}
+			var ok bool
+			switch m := member.(type) {
+			case map[string]interface{}:
+				var record interface{}
+				if record, ok = recordFor(m); ok {
+					union = goavro.Union(dataRecord, record)
+				}
+			default:
+				union, ok = scalarFor(m)
+			}
+			if !ok {
+				return nil, false
+			}
+			unions[k] = union
+		}
+		return goavro.Union("map", unions), true
+	case []interface{}:
+		records, ok := recordsFor(v)
+		if !ok {
+			return nil, false
+		}
+		return goavro.Union("array", records), true
+	default:
+		return nil, false
+	}
+}
+
+// recordFor returns the CloudEventData record of a JSON object, if the schema can represent it.
+func recordFor(object map[string]interface{}) (interface{}, bool) {
+	unions := make(map[string]interface{}, len(object))
+	for k, member := range object {
+		switch m := member.(type) {
+		case map[string]interface{}:
+			// Only the objects of objects can be represented
+			records := make(map[string]interface{}, len(m))
+			for rk, rv := range m {
+				o, ok := rv.(map[string]interface{})
+				if !ok {
+					return nil, false
+				}
+				if records[rk], ok = recordFor(o); !ok {
+					return nil, false
+				}
+			}
+			unions[k] = goavro.Union("map", records)
+		case []interface{}:
+			records, ok := recordsFor(m)
+			if !ok {
+				return nil, false
+			}
+			unions[k] = goavro.Union("array", records)
+		default:
+			union, ok := scalarFor(m)
+			if !ok {
+				return nil, false
+			}
+			unions[k] = union
+		}
+	}
+	return map[string]interface{}{"value": unions}, true
+}
+
+// recordsFor returns the CloudEventData records of a JSON array, if it's an array of objects.
+func recordsFor(array []interface{}) ([]interface{}, bool) {
+	records := make([]interface{}, len(array))
+	for i, item := range array {
+		object, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if records[i], ok = recordFor(object); !ok {
+			return nil, false
+		}
+	}
+	return records, true
+}
+
+// scalarFor returns the union of a JSON null, boolean, number or string.
+func scalarFor(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return goavro.Union("null", nil), true
+	case bool:
+		return goavro.Union("boolean", v), true
+	case json.Number:
+		f, ok := doubleFor(v)
+		if !ok {
+			return nil, false
+		}
+		return goavro.Union("double", f), true
+	case string:
+		return goavro.Union("string", v), true
+	default:
+		return nil, false
+	}
+}
+
+// FromAvro converts the native goavro representation of an event, decoded with the Schema,
+// to an SDK event.
+// The data stored as a JSON value is converted to JSON, with the "application/json"
+// content type if the event has none.
+func FromAvro(native interface{}) (*event.Event, error) {
+	record, ok := native.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected Avro event %T", native)
+	}
+	attributes, _ := record["attribute"].(map[string]interface{})
+	values := make(map[string]interface{}, len(attributes))
+	for name, attr := range attributes {
+		values[name] = unionValue(attr)
+	}
+
+	sv, _ := values[specversion].(string)
+	version := spec.VS.Version(sv)
+	if version == nil {
+		return nil, fmt.Errorf("unsupported spec version %q", sv)
+	}
+	e := event.New(sv)
+	for name, value := range values {
+		if name == specversion || value == nil {
+			continue
+		}
+		if err := version.SetAttribute(e.Context, name, value); err != nil {
+			return nil, fmt.Errorf("failed to convert attribute %s: %w", name, err)
+		}
+	}
+
+	data, ok := record["data"].(map[string]interface{})
+	if !ok {
+		return &e, nil
+	}
+	for branch, value := range data {
+		switch branch {
+		case "bytes":
+			e.DataEncoded, _ = value.([]byte)
+			e.DataBase64 = true
+		case "string":
+			s, _ := value.(string)
+			e.DataEncoded = []byte(s)
+		default:
+			b, err := json.Marshal(dataValue(branch, value))
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert data: %w", err)
+			}
+			e.DataEncoded = b
+			if e.DataContentType() == "" {
+				e.SetDataContentType(event.ApplicationJSON)
+			}
+		}
+	}
+	return &e, nil
+}
+
+// unionValue returns the value of a union, in its native goavro representation.
+// The values of the CloudEventData records are converted to JSON objects.
+func unionValue(union interface{}) interface{} {
+	m, ok := union.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	for branch, value := range m {
+		switch branch {
+		case dataRecord:
+			return recordValue(value)
+		case "map":
+			records, _ := value.(map[string]interface{})
+			object := make(map[string]interface{}, len(records))
+			for k, r := range records {
+				object[k] = recordValue(r)
+			}
+			return object
+		case "array":
+			return arrayValue(value)
+		default:
+			return value
+		}
+	}
+	return nil
+}
+
+// recordValue returns the JSON object of a CloudEventData record.
+func recordValue(record interface{}) interface{} {
+	r, _ := record.(map[string]interface{})
+	unions, _ := r["value"].(map[string]interface{})
+	object := make(map[string]interface{}, len(unions))
+	for k, u := range unions {
+		object[k] = unionValue(u)
+	}
+	return object
+}
+
+// arrayValue returns the JSON array of an array of CloudEventData records.
+func arrayValue(array interface{}) interface{} {
+	records, _ := array.([]interface{})
+	values := make([]interface{}, len(records))
+	for i, r := range records {
+		values[i] = recordValue(r)
+	}
+	return values
+}
+
+// dataValue returns the JSON value of a branch of the union of the data.
+func dataValue(branch string, value interface{}) interface{} {
+	switch branch {
+	case "map":
+		unions, _ := value.(map[string]interface{})
+		object := make(map[string]interface{}, len(unions))
+		for k, u := range unions {
+			object[k] = unionValue(u)
+		}
+		return object
+	case "array":
+		return arrayValue(value)
+	default:
+		return value
+	}
+}
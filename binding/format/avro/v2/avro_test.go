@@ -0,0 +1,241 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package format_test
+
+import (
+	"testing"
+	stdtime "time"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/test"
+	"github.com/cloudevents/sdk-go/v2/types"
+
+	avro "github.com/cloudevents/sdk-go/binding/format/avro/v2"
+)
+
+// testEvent returns test.FullEvent, and the same event as read back from the Avro format,
+// where URIs and timestamps extensions are strings.
+func testEvent(t *testing.T) (event.Event, event.Event) {
+	e := test.FullEvent()
+	want := e.Clone()
+	for _, name := range []string{"exurl", "extime"} {
+		s, err := types.Format(e.Extensions()[name])
+		require.NoError(t, err)
+		want.SetExtension(name, s)
+	}
+	return e, want
+}
+
+func TestAvroFormat(t *testing.T) {
+	require.Equal(t, avro.Avro, format.Lookup(avro.ApplicationCloudEventsAvro))
+
+	for name, setData := range map[string]func(e *event.Event) error{
+		"no data": func(e *event.Event) error {
+			e.DataEncoded = nil
+			return nil
+		},
+		"json data": func(e *event.Event) error {
+			return e.SetData(event.ApplicationJSON, "foo")
+		},
+		"text data": func(e *event.Event) error {
+			return e.SetData(event.TextPlain, "foo")
+		},
+		"binary data": func(e *event.Event) error {
+			return e.SetData("application/octet-stream", []byte{0, 1, 2})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			e, want := testEvent(t)
+			require.NoError(t, setData(&e))
+			require.NoError(t, setData(&want))
+
+			b, err := format.Marshal(avro.ApplicationCloudEventsAvro, &e)
+			require.NoError(t, err)
+			var e2 event.Event
+			require.NoError(t, format.Unmarshal(avro.ApplicationCloudEventsAvro, b, &e2))
+			require.Equal(t, want, e2)
+		})
+	}
+}
+
+func TestAvroFormatV03(t *testing.T) {
+	e := event.New(event.CloudEventsVersionV03)
+	e.SetID("test")
+	e.SetSource("test")
+	e.SetType("test")
+	e.SetDataSchema("http://example.com/schema")
+	require.NoError(t, e.SetData(event.ApplicationJSON, map[string]string{"hello": "world"}))
+
+	b, err := avro.Avro.Marshal(&e)
+	require.NoError(t, err)
+	var e2 event.Event
+	require.NoError(t, avro.Avro.Unmarshal(b, &e2))
+	require.Equal(t, e, e2)
+}
+
+func TestAvroFormatJSONData(t *testing.T) {
+	codec, err := goavro.NewCodec(avro.Schema)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        string
+		wantBranch  string
+	}{
+		{
+			name:        "object",
+			contentType: event.ApplicationJSON,
+			data:        `{"null":null,"bool":true,"n":1.5,"s":"s","object":{"array":[{"n":1}],"objects":{"a":{"b":"c"}}}}`,
+			wantBranch:  "map",
+		},
+		{
+			name:        "array",
+			contentType: "application/vnd.test+json; charset=utf-8",
+			data:        `[{"a":1},{"b":[{"c":false}]}]`,
+			wantBranch:  "array",
+		},
+		{
+			name:        "number",
+			contentType: event.ApplicationJSON,
+			data:        `2`,
+			wantBranch:  "double",
+		},
+		{
+			name:        "decimal number",
+			contentType: event.ApplicationJSON,
+			data:        `0.1`,
+			wantBranch:  "double",
+		},
+		{
+			name:        "large integer",
+			contentType: event.ApplicationJSON,
+			data:        `12345678901234567890`,
+			wantBranch:  "string",
+		},
+		{
+			name:        "object with large integer",
+			contentType: event.ApplicationJSON,
+			data:        `{"id":9007199254740993,"n":1}`,
+			wantBranch:  "string",
+		},
+		{
+			name:        "string",
+			contentType: event.ApplicationJSON,
+			data:        `"s"`,
+			wantBranch:  "string",
+		},
+		{
+			name:        "array of numbers",
+			contentType: event.ApplicationJSON,
+			data:        `[1,2]`,
+			wantBranch:  "string",
+		},
+		{
+			name:        "not json",
+			contentType: event.TextPlain,
+			data:        `{"a":1}`,
+			wantBranch:  "string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := test.MinEvent()
+			e.SetDataContentType(tt.contentType)
+			e.DataEncoded = []byte(tt.data)
+
+			b, err := avro.Avro.Marshal(&e)
+			require.NoError(t, err)
+			native, _, err := codec.NativeFromBinary(b)
+			require.NoError(t, err)
+			data, _ := native.(map[string]interface{})["data"].(map[string]interface{})
+			require.Contains(t, data, tt.wantBranch)
+
+			var e2 event.Event
+			require.NoError(t, avro.Avro.Unmarshal(b, &e2))
+			require.Equal(t, tt.contentType, e2.DataContentType())
+			if tt.wantBranch == "string" {
+				require.Equal(t, tt.data, string(e2.Data()))
+			} else {
+				require.JSONEq(t, tt.data, string(e2.Data()))
+			}
+		})
+	}
+}
+
+func TestFromAvro(t *testing.T) {
+	codec, err := goavro.NewCodec(avro.Schema)
+	require.NoError(t, err)
+	attributes := map[string]interface{}{
+		"specversion": goavro.Union("string", "1.0"),
+		"id":          goavro.Union("string", "1"),
+		"source":      goavro.Union("string", "/source"),
+		"type":        goavro.Union("string", "test"),
+		"time":        goavro.Union("string", "2021-01-01T01:01:01Z"),
+		"ext":         goavro.Union("int", int32(1)),
+	}
+	record := func(value map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"value": value}
+	}
+
+	tests := []struct {
+		name     string
+		data     interface{}
+		wantData string
+		wantErr  string
+	}{
+		{
+			name: "no data",
+		},
+		{
+			name:     "double",
+			data:     goavro.Union("double", 1.5),
+			wantData: `1.5`,
+		},
+		{
+			name: "object",
+			data: goavro.Union("map", map[string]interface{}{
+				"null":   nil,
+				"string": goavro.Union("string", "s"),
+				"object": goavro.Union("io.cloudevents.CloudEventData", record(map[string]interface{}{
+					"bool":  goavro.Union("boolean", true),
+					"array": goavro.Union("array", []interface{}{record(map[string]interface{}{"n": goavro.Union("double", 1.0)})}),
+				})),
+			}),
+			wantData: `{"null":null,"object":{"array":[{"n":1}],"bool":true},"string":"s"}`,
+		},
+		{
+			name:     "array",
+			data:     goavro.Union("array", []interface{}{record(map[string]interface{}{})}),
+			wantData: `[{}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := codec.BinaryFromNative(nil, map[string]interface{}{"attribute": attributes, "data": tt.data})
+			require.NoError(t, err)
+			var e event.Event
+			require.NoError(t, avro.Avro.Unmarshal(b, &e))
+			require.NoError(t, e.Validate())
+			require.Equal(t, "1", e.ID())
+			require.Equal(t, stdtime.Date(2021, 1, 1, 1, 1, 1, 0, stdtime.UTC), e.Time())
+			require.Equal(t, int32(1), e.Extensions()["ext"])
+			if tt.wantData == "" {
+				require.Nil(t, e.Data())
+				return
+			}
+			require.Equal(t, event.ApplicationJSON, e.DataContentType())
+			require.JSONEq(t, tt.wantData, string(e.Data()))
+		})
+	}
+
+	_, err = avro.FromAvro(map[string]interface{}{"attribute": map[string]interface{}{}})
+	require.ErrorContains(t, err, "unsupported spec version")
+}
@@ -0,0 +1,41 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package format_test
+
+import (
+	"testing"
+
+	"github.com/linkedin/goavro/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+
+	avro "github.com/cloudevents/sdk-go/binding/format/avro/v2"
+)
+
+func TestAvroFormatWithAvroCodec(t *testing.T) {
+	codec, err := goavro.NewCodec(`{"type": "record", "name": "Order", "fields": [{"name": "id", "type": "long"}, {"name": "item", "type": "string"}]}`)
+	require.NoError(t, err)
+
+	e, want := testEvent(t)
+	payload := avro.Datum{Codec: codec, Native: map[string]interface{}{"id": int64(1), "item": "book"}}
+	require.NoError(t, e.SetData(avro.ContentTypeAvro, payload))
+	require.NoError(t, want.SetData(avro.ContentTypeAvro, &payload))
+
+	b, err := avro.Avro.Marshal(&e)
+	require.NoError(t, err)
+	var e2 event.Event
+	require.NoError(t, avro.Avro.Unmarshal(b, &e2))
+	require.Equal(t, want, e2)
+
+	payload2 := &avro.Datum{Codec: codec}
+	require.NoError(t, e2.DataAs(payload2))
+	require.Equal(t, payload.Native, payload2.Native)
+
+	require.ErrorContains(t, e2.DataAs(&avro.Datum{}), "without codec")
+	require.ErrorContains(t, e2.DataAs(&map[string]interface{}{}), "can only decode avro into *Datum")
+	require.ErrorContains(t, e.SetData(avro.ContentTypeAvro, "not a datum"), "only works with avro datums")
+}
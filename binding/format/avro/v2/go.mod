@@ -0,0 +1,22 @@
+module github.com/cloudevents/sdk-go/binding/format/avro/v2
+
+go 1.24.0
+
+require (
+	github.com/cloudevents/sdk-go/v2 v2.16.2
+	github.com/linkedin/goavro/v2 v2.12.0
+	github.com/stretchr/testify v1.11.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/snappy v0.0.1 // indirect
+	github.com/google/go-cmp v0.7.0 // indirect
+	github.com/json-iterator/go v1.1.12 // indirect
+	github.com/modern-go/concurrent v0.0.0-20180306012644-bacd9c7ef1dd // indirect
+	github.com/modern-go/reflect2 v1.0.2 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/cloudevents/sdk-go/v2 => ../../../../v2
@@ -6,7 +6,10 @@
 package format
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"net/url"
 	stdtime "time"
 
@@ -32,10 +35,13 @@ var (
 	zeroTime = stdtime.Time{}
 	// Protobuf is the built-in "application/cloudevents+protobuf" format.
 	Protobuf = protobufFmt{}
+	// ProtobufBatch is the built-in "application/cloudevents-batch+protobuf" format.
+	ProtobufBatch = protobufBatchFmt{}
 )
 
 const (
-	ApplicationCloudEventsProtobuf = "application/cloudevents+protobuf"
+	ApplicationCloudEventsProtobuf      = "application/cloudevents+protobuf"
+	ApplicationCloudEventsBatchProtobuf = "application/cloudevents-batch+protobuf"
 )
 
 // StringOfApplicationCloudEventsProtobuf  returns a string pointer to
@@ -45,8 +51,16 @@ func StringOfApplicationCloudEventsProtobuf() *string {
 	return &a
 }
 
+// StringOfApplicationCloudEventsBatchProtobuf returns a string pointer to
+// "application/cloudevents-batch+protobuf"
+func StringOfApplicationCloudEventsBatchProtobuf() *string {
+	a := ApplicationCloudEventsBatchProtobuf
+	return &a
+}
+
 func init() {
 	format.Add(Protobuf)
+	format.Add(ProtobufBatch)
 }
 
 type protobufFmt struct{}
@@ -75,6 +89,75 @@ func (protobufFmt) Unmarshal(b []byte, e *event.Event) error {
 	return nil
 }
 
+type protobufBatchFmt struct{}
+
+func (protobufBatchFmt) MediaType() string {
+	return ApplicationCloudEventsBatchProtobuf
+}
+
+// Marshal will return an error for protobufBatchFmt, use MarshalBatch instead.
+func (protobufBatchFmt) Marshal(e *event.Event) ([]byte, error) {
+	return nil, errors.New("not supported for batch events")
+}
+
+// Unmarshal will return an error for protobufBatchFmt, use UnmarshalBatch instead.
+func (protobufBatchFmt) Unmarshal(b []byte, e *event.Event) error {
+	return errors.New("not supported for batch events")
+}
+
+// MarshalBatch marshals the events as a pb.CloudEventBatch.
+func (protobufBatchFmt) MarshalBatch(events []event.Event) ([]byte, error) {
+	batch := &pb.CloudEventBatch{Events: make([]*pb.CloudEvent, len(events))}
+	for i := range events {
+		pbe, err := ToProto(&events[i])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal event %d: %w", i, err)
+		}
+		batch.Events[i] = pbe
+	}
+	return proto.Marshal(batch)
+}
+
+// UnmarshalBatch unmarshals the events of a pb.CloudEventBatch.
+func (protobufBatchFmt) UnmarshalBatch(b []byte) ([]event.Event, error) {
+	batch := &pb.CloudEventBatch{}
+	if err := proto.Unmarshal(b, batch); err != nil {
+		return nil, err
+	}
+	events := make([]event.Event, len(batch.Events))
+	for i, pbe := range batch.Events {
+		e, err := FromProto(pbe)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event %d: %w", i, err)
+		}
+		events[i] = *e
+	}
+	return events, nil
+}
+
+// DecodeBatch reads the whole pb.CloudEventBatch from r, then iterates over its events.
+func (f protobufBatchFmt) DecodeBatch(r io.Reader) iter.Seq2[event.Event, error] {
+	return func(yield func(event.Event, error) bool) {
+		b, err := io.ReadAll(r)
+		if err != nil {
+			yield(event.Event{}, err)
+			return
+		}
+		events, err := f.UnmarshalBatch(b)
+		if err != nil {
+			yield(event.Event{}, err)
+			return
+		}
+		for _, e := range events {
+			if !yield(e, nil) {
+				return
+			}
+		}
+	}
+}
+
+var _ format.BatchFormat = ProtobufBatch // Test it conforms to the interface
+
 // convert an SDK event to a protobuf variant of the event that can be marshaled.
 func ToProto(e *event.Event) (*pb.CloudEvent, error) {
 	container := &pb.CloudEvent{
@@ -6,14 +6,17 @@
 package format_test
 
 import (
+	"bytes"
 	"net/url"
 	"reflect"
 	"testing"
 	stdtime "time"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	coreformat "github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/types"
 
@@ -214,3 +217,42 @@ func TestFromProto(t *testing.T) {
 		})
 	}
 }
+
+func TestProtobufBatchFormat(t *testing.T) {
+	require := require.New(t)
+	e1 := event.New()
+	e1.SetID("1")
+	e1.SetSource("test")
+	e1.SetType("test")
+	e1.SetExtension("int", 1)
+	require.NoError(e1.SetData(event.ApplicationJSON, "foo"))
+	e2 := event.New()
+	e2.SetID("2")
+	e2.SetSource("test")
+	e2.SetType("test")
+	require.NoError(e2.SetData(event.TextPlain, "bar"))
+	events := []event.Event{e1, e2}
+
+	b, err := format.ProtobufBatch.MarshalBatch(events)
+	require.NoError(err)
+	got, err := format.ProtobufBatch.UnmarshalBatch(b)
+	require.NoError(err)
+	require.Equal(events, got)
+
+	require.Equal(format.ProtobufBatch, coreformat.LookupBatch(format.ApplicationCloudEventsBatchProtobuf))
+	var decoded []event.Event
+	for e, err := range format.ProtobufBatch.DecodeBatch(bytes.NewReader(b)) {
+		require.NoError(err)
+		decoded = append(decoded, e)
+	}
+	require.Equal(events, decoded)
+
+	pbb := &pb.CloudEventBatch{}
+	require.NoError(proto.Unmarshal(b, pbb))
+	require.Len(pbb.Events, 2)
+
+	_, err = format.ProtobufBatch.Marshal(&e1)
+	require.Error(err)
+	_, err = format.ProtobufBatch.UnmarshalBatch([]byte{0xff})
+	require.Error(err)
+}
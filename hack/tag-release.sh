@@ -25,10 +25,12 @@ MODULES=(
   "protocol/pubsub"
   "protocol/kafka_sarama"
   "protocol/ws"
+  "protocol/http3"
   "observability/opencensus"
   "observability/opentelemetry"
   "sql"
   "binding/format/protobuf"
+  "binding/format/avro"
 )
 
 REPOINT=(
@@ -44,10 +46,12 @@ REPOINT_SAMPLES=(
   "github.com/cloudevents/sdk-go/protocol/pubsub/v2"
   "github.com/cloudevents/sdk-go/protocol/kafka_sarama/v2"
   "github.com/cloudevents/sdk-go/protocol/ws/v2"
+  "github.com/cloudevents/sdk-go/protocol/http3/v2"
   "github.com/cloudevents/sdk-go/observability/opencensus/v2"
   "github.com/cloudevents/sdk-go/observability/opentelemetry/v2"
   "github.com/cloudevents/sdk-go/sql/v2"
   "github.com/cloudevents/sdk-go/binding/format/protobuf/v2"
+  "github.com/cloudevents/sdk-go/binding/format/avro/v2"
   "github.com/cloudevents/sdk-go/v2"                       # NOTE: this needs to be last.
 )
 
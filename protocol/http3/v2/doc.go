@@ -0,0 +1,10 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+/*
+Package http3 extends the HTTP protocol binding to send and receive events over HTTP/3,
+using github.com/quic-go/quic-go module.
+*/
+package http3
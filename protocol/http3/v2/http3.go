@@ -0,0 +1,79 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http3
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+// Server is a cehttp.InboundServer serving the HTTP protocol handler over HTTP/3.
+type Server struct {
+	conn   net.PacketConn
+	server *http3.Server
+}
+
+var _ cehttp.InboundServer = (*Server)(nil)
+
+// NewServer returns a Server listening on the UDP address addr, e.g. ":8443".
+// The TLS config must provide the server certificates, as HTTP/3 always runs over TLS.
+// The optional QUIC config tunes the QUIC connections.
+func NewServer(addr string, tlsConfig *tls.Config, quicConfig *quic.Config) (*Server, error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{
+		conn: conn,
+		server: &http3.Server{
+			TLSConfig:  http3.ConfigureTLSConfig(tlsConfig),
+			QUICConfig: quicConfig,
+		},
+	}, nil
+}
+
+// Addr returns the UDP address the server is listening on.
+func (s *Server) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+func (s *Server) Serve(handler http.Handler) error {
+	s.server.Handler = handler
+	return s.server.Serve(s.conn)
+}
+
+func (s *Server) Shutdown(ctx context.Context) error {
+	err := s.server.Shutdown(ctx)
+	_ = s.conn.Close()
+	return err
+}
+
+func (s *Server) Close() error {
+	err := s.server.Close()
+	_ = s.conn.Close()
+	return err
+}
+
+// WithServer makes the HTTP protocol receive the events over HTTP/3 through server.
+func WithServer(server *Server) cehttp.Option {
+	return cehttp.WithInboundServer(server)
+}
+
+// WithTransport makes the HTTP protocol send the events over HTTP/3.
+// The targets must be https URLs, verified with the optional TLS config.
+func WithTransport(tlsConfig *tls.Config, quicConfig *quic.Config) cehttp.Option {
+	return cehttp.WithRoundTripper(&http3.Transport{
+		TLSClientConfig: tlsConfig,
+		QUICConfig:      quicConfig,
+	})
+}
@@ -0,0 +1,106 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http3
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+)
+
+// selfSignedTLS returns the server TLS config with a certificate for 127.0.0.1,
+// and the client TLS config trusting it.
+func selfSignedTLS(t *testing.T) (*tls.Config, *tls.Config) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}},
+		&tls.Config{RootCAs: pool}
+}
+
+func TestSendReceive(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	serverTLS, clientTLS := selfSignedTLS(t)
+	server, err := NewServer("127.0.0.1:0", serverTLS, nil)
+	require.NoError(t, err)
+
+	protos := make(chan string, 1)
+	receiver, err := cehttp.New(WithServer(server), cehttp.WithMiddleware(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			protos <- req.Proto
+			next.ServeHTTP(rw, req)
+		})
+	}))
+	require.NoError(t, err)
+
+	opened := make(chan error, 1)
+	go func() {
+		opened <- receiver.OpenInbound(ctx)
+	}()
+	received := make(chan *event.Event, 1)
+	go func() {
+		m, fn, err := receiver.Respond(ctx)
+		if err != nil {
+			return
+		}
+		e, err := binding.ToEvent(ctx, m)
+		_ = m.Finish(err)
+		_ = fn(ctx, nil, err)
+		received <- e
+	}()
+
+	sender, err := cehttp.New(cehttp.WithTarget("https://"+server.Addr().String()), WithTransport(clientTLS, nil))
+	require.NoError(t, err)
+
+	e := event.New()
+	e.SetID("1")
+	e.SetType("unit.test.http3")
+	e.SetSource("/unit/test/http3")
+	require.NoError(t, e.SetData(event.ApplicationJSON, map[string]string{"hello": "world"}))
+
+	result := sender.Send(ctx, binding.ToMessage(&e))
+	require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+	require.Equal(t, "HTTP/3.0", <-protos)
+	got := <-received
+	require.Equal(t, e.ID(), got.ID())
+	require.Equal(t, e.Data(), got.Data())
+
+	cancel()
+	require.NoError(t, <-opened)
+}
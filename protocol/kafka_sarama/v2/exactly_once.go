@@ -0,0 +1,112 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBM/sarama"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// ConfigureExactlyOnce sets up the sarama configuration required by the exactly-once mode
+// (see WithExactlyOnce): an idempotent and transactional producer with the provided transactional id,
+// and a consumer reading only committed messages, whose offsets are committed within the producer transactions.
+func ConfigureExactlyOnce(config *sarama.Config, transactionalID string) {
+	if !config.Version.IsAtLeast(sarama.V0_11_0_0) {
+		config.Version = sarama.V0_11_0_0
+	}
+	config.Producer.Idempotent = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Transaction.ID = transactionalID
+	config.Net.MaxOpenRequests = 1
+	config.Consumer.IsolationLevel = sarama.ReadCommitted
+	config.Consumer.Offsets.AutoCommit.Enable = false
+}
+
+// transactor runs a producer transaction for each consumed message.
+// Because a transactional producer can run only one transaction at a time, messages are processed one by one.
+type transactor struct {
+	// running holds a token while a transaction is in progress.
+	running  chan struct{}
+	producer sarama.SyncProducer
+	groupId  string
+}
+
+func newTransactor(producer sarama.SyncProducer, groupId string) *transactor {
+	return &transactor{running: make(chan struct{}, 1), producer: producer, groupId: groupId}
+}
+
+// begin starts the transaction of cm, waiting for the previous one to complete or for ctx to be done.
+func (t *transactor) begin(ctx context.Context, cm *sarama.ConsumerMessage) (*exactlyOnceMessage, error) {
+	select {
+	case t.running <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if err := t.producer.BeginTxn(); err != nil {
+		<-t.running
+		return nil, err
+	}
+	return &exactlyOnceMessage{
+		Message:  NewMessageFromConsumerMessage(cm),
+		consumed: cm,
+		txn:      t,
+	}, nil
+}
+
+// exactlyOnceMessage is a Message whose consumed offset is committed in the producer transaction,
+// together with the messages sent by the Sender while the message is processed.
+type exactlyOnceMessage struct {
+	*Message
+	consumed *sarama.ConsumerMessage
+	txn      *transactor
+
+	once sync.Once
+	err  error
+}
+
+var (
+	_ binding.ExactlyOnceMessage = (*exactlyOnceMessage)(nil)
+	_ binding.MessageWrapper     = (*exactlyOnceMessage)(nil)
+)
+
+// Received commits the transaction and settles the message with the commit result.
+func (m *exactlyOnceMessage) Received(settle func(error)) {
+	settle(m.end(true))
+}
+
+// Finish commits the transaction if err is an ACK and the message was not settled yet, otherwise it aborts it.
+func (m *exactlyOnceMessage) Finish(err error) error {
+	return m.end(protocol.IsACK(err))
+}
+
+func (m *exactlyOnceMessage) GetWrappedMessage() binding.Message {
+	return m.Message
+}
+
+func (m *exactlyOnceMessage) end(commit bool) error {
+	m.once.Do(func() {
+		defer func() { <-m.txn.running }()
+		producer := m.txn.producer
+		if commit {
+			if m.err = producer.AddMessageToTxn(m.consumed, m.txn.groupId, nil); m.err == nil {
+				m.err = producer.CommitTxn()
+			}
+			if m.err == nil {
+				return
+			}
+		}
+		if err := producer.AbortTxn(); m.err == nil {
+			m.err = err
+		}
+	})
+	return m.err
+}
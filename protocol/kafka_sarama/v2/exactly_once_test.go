@@ -0,0 +1,111 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package kafka_sarama
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+func TestExactlyOnceMessage(t *testing.T) {
+	producer := &syncProducerMock{isTransactional: true, status: sarama.ProducerTxnFlagReady}
+	txn := newTransactor(producer, "group")
+
+	first := &sarama.ConsumerMessage{Topic: "aaa", Offset: 1}
+	m, err := txn.begin(context.Background(), first)
+	require.NoError(t, err)
+	require.Equal(t, sarama.ProducerTxnFlagInTransaction, producer.TxnStatus())
+	var _ binding.ExactlyOnceMessage = m
+
+	// The next transaction starts only when the current one completes
+	began := make(chan *exactlyOnceMessage)
+	go func() {
+		next, err := txn.begin(context.Background(), &sarama.ConsumerMessage{Topic: "aaa", Offset: 2})
+		require.NoError(t, err)
+		began <- next
+	}()
+	select {
+	case <-began:
+		t.Fatal("transaction started while another one is in progress")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	var settled error = protocol.ResultNACK
+	m.Received(func(err error) { settled = err })
+	require.NoError(t, settled)
+	require.NoError(t, m.Finish(nil))
+	require.Equal(t, 1, producer.committed)
+	require.Equal(t, []*sarama.ConsumerMessage{first}, producer.txnMessages)
+
+	next := <-began
+	require.NoError(t, next.Finish(protocol.ResultNACK))
+	require.Equal(t, 1, producer.committed)
+	require.Equal(t, 1, producer.aborted)
+	require.Len(t, producer.txnMessages, 1)
+}
+
+type consumerGroupSessionMock struct {
+	sarama.ConsumerGroupSession
+	ctx context.Context
+}
+
+func (s consumerGroupSessionMock) Context() context.Context {
+	return s.ctx
+}
+
+type consumerGroupClaimMock struct {
+	sarama.ConsumerGroupClaim
+	messages chan *sarama.ConsumerMessage
+}
+
+func (c consumerGroupClaimMock) Messages() <-chan *sarama.ConsumerMessage {
+	return c.messages
+}
+
+func TestConsumeClaimExactlyOnceCanceledSession(t *testing.T) {
+	producer := &syncProducerMock{isTransactional: true, status: sarama.ProducerTxnFlagReady}
+	r := NewReceiver()
+	r.txn = newTransactor(producer, "group")
+
+	// The transaction of a message received before the rebalance is still in progress
+	inProgress, err := r.txn.begin(context.Background(), &sarama.ConsumerMessage{Topic: "aaa", Offset: 1})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	claim := consumerGroupClaimMock{messages: make(chan *sarama.ConsumerMessage, 1)}
+	claim.messages <- &sarama.ConsumerMessage{Topic: "aaa", Offset: 2}
+	returned := make(chan error)
+	go func() {
+		returned <- r.ConsumeClaim(consumerGroupSessionMock{ctx: ctx}, claim)
+	}()
+
+	// ConsumeClaim returns once the session is canceled, without waiting for the transaction
+	cancel()
+	select {
+	case err := <-returned:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("ConsumeClaim blocked after the session was canceled")
+	}
+
+	require.NoError(t, inProgress.Finish(protocol.ResultNACK))
+	require.Equal(t, 1, producer.aborted)
+}
+
+func TestConfigureExactlyOnce(t *testing.T) {
+	config := sarama.NewConfig()
+	ConfigureExactlyOnce(config, "txn-id")
+	require.NoError(t, config.Validate())
+	require.True(t, config.Producer.Idempotent)
+	require.Equal(t, sarama.ReadCommitted, config.Consumer.IsolationLevel)
+}
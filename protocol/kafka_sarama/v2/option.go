@@ -26,3 +26,15 @@ func WithSenderContextDecorators(decorator func(context.Context) context.Context
 		protocol.SenderContextDecorators = append(protocol.SenderContextDecorators, decorator)
 	}
 }
+
+// WithExactlyOnce makes the protocol commit the offset of each received message within a transaction
+// of the Sender producer, together with the messages sent while the received message is processed.
+// The received messages implement binding.ExactlyOnceMessage, so they can be settled by the client
+// configured with client.WithExactlyOnce. Messages are processed one at a time, and the Sender can
+// be used only while processing a received message.
+// The sarama configuration must be set up with ConfigureExactlyOnce.
+func WithExactlyOnce() ProtocolOptionFunc {
+	return func(protocol *Protocol) {
+		protocol.exactlyOnce = true
+	}
+}
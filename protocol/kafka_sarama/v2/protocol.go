@@ -40,6 +40,7 @@ type Protocol struct {
 	// Consumer options
 	receiverTopic   string
 	receiverGroupId string
+	exactlyOnce     bool
 }
 
 // NewProtocol creates a new kafka transport.
@@ -88,6 +89,13 @@ func NewProtocolFromClient(client sarama.Client, sendToTopic string, receiveFrom
 	}
 	p.Consumer = NewConsumerFromClient(p.Client, p.receiverGroupId, p.receiverTopic)
 
+	if p.exactlyOnce {
+		if !p.Sender.syncProducer.IsTransactional() {
+			return nil, errors.New("exactly-once requires a transactional producer, configure sarama with ConfigureExactlyOnce")
+		}
+		p.Consumer.txn = newTransactor(p.Sender.syncProducer, p.receiverGroupId)
+	}
+
 	return p, nil
 }
 
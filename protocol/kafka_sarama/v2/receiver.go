@@ -25,6 +25,9 @@ type msgErr struct {
 type Receiver struct {
 	once     sync.Once
 	incoming chan msgErr
+
+	// txn is set in exactly-once mode
+	txn *transactor
 }
 
 // NewReceiver creates a Receiver which implements sarama.ConsumerGroupHandler
@@ -65,13 +68,25 @@ func (r *Receiver) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 			if !ok {
 				return nil
 			}
-			m := NewMessageFromConsumerMessage(msg)
-			msgErrObj := msgErr{
-				msg: binding.WithFinish(m, func(err error) {
+			var msgErrObj msgErr
+			if r.txn != nil {
+				// The offset is committed within the transaction, when the message is settled or finished
+				// The previous transaction may never complete if the session ends, e.g. on a rebalance
+				m, err := r.txn.begin(session.Context(), msg)
+				if err != nil {
+					if session.Context().Err() != nil {
+						return nil
+					}
+					return err
+				}
+				msgErrObj.msg = m
+			} else {
+				m := NewMessageFromConsumerMessage(msg)
+				msgErrObj.msg = binding.WithFinish(m, func(err error) {
 					if protocol.IsACK(err) {
 						session.MarkMessage(msg, "")
 					}
-				}),
+				})
 			}
 
 			// Need to use select clause here, otherwise r.incoming <- msgErrObj can become a blocking operation,
@@ -80,6 +95,7 @@ func (r *Receiver) ConsumeClaim(session sarama.ConsumerGroupSession, claim saram
 			case r.incoming <- msgErrObj:
 				// do nothing
 			case <-session.Context().Done():
+				_ = msgErrObj.msg.Finish(protocol.ResultNACK)
 				return nil
 			}
 
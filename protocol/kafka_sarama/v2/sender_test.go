@@ -21,6 +21,9 @@ type syncProducerMock struct {
 	sent            []*sarama.ProducerMessage
 	isTransactional bool
 	status          sarama.ProducerTxnStatusFlag
+	txnMessages     []*sarama.ConsumerMessage
+	committed       int
+	aborted         int
 }
 
 func (s *syncProducerMock) SendMessage(msg *sarama.ProducerMessage) (partition int32, offset int64, err error) {
@@ -62,6 +65,7 @@ func (s *syncProducerMock) CommitTxn() error {
 	defer s.lock.Unlock()
 
 	s.status = sarama.ProducerTxnFlagReady
+	s.committed++
 	return nil
 }
 
@@ -70,6 +74,7 @@ func (s *syncProducerMock) AbortTxn() error {
 	defer s.lock.Unlock()
 
 	s.status = sarama.ProducerTxnFlagReady
+	s.aborted++
 	return nil
 }
 
@@ -84,6 +89,9 @@ func (s *syncProducerMock) AddOffsetsToTxn(offsets map[string][]*sarama.Partitio
 }
 
 func (s *syncProducerMock) AddMessageToTxn(msg *sarama.ConsumerMessage, groupId string, metadata *string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.txnMessages = append(s.txnMessages, msg)
 	return nil
 }
 
@@ -39,7 +39,7 @@ func Dial(ctx context.Context, u string, opts *websocket.DialOptions) (*ClientPr
 	if opts == nil {
 		opts = &websocket.DialOptions{}
 	}
-	opts.Subprotocols = SupportedSubprotocols
+	opts.Subprotocols = availableSubprotocols()
 	c, _, err := websocket.Dial(ctx, u, opts)
 	if err != nil {
 		return nil, err
@@ -72,7 +72,7 @@ func (c *ClientProtocol) Send(ctx context.Context, m binding.Message, transforme
 	if err != nil {
 		return err
 	}
-	return utils.WriteStructured(ctx, m, writer, transformers...)
+	return utils.WriteStructured(binding.UseFormatForEvent(ctx, c.format), m, writer, transformers...)
 }
 
 func (c *ClientProtocol) Receive(ctx context.Context) (binding.Message, error) {
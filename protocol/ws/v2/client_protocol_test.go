@@ -81,7 +81,7 @@ func TestClientProtocolPingPongWithClient(t *testing.T) {
 
 func pingPongHandler(t *testing.T) http.HandlerFunc {
 	return func(writer http.ResponseWriter, request *http.Request) {
-		c, err := websocket.Accept(writer, request, &websocket.AcceptOptions{Subprotocols: SupportedSubprotocols})
+		c, err := websocket.Accept(writer, request, &websocket.AcceptOptions{Subprotocols: Subprotocols()})
 
 		require.NoError(t, err)
 		require.Equal(t, JsonSubprotocol, c.Subprotocol())
@@ -104,3 +104,45 @@ func pingPongHandler(t *testing.T) http.HandlerFunc {
 		<-ctx.Done()
 	}
 }
+
+func TestClientProtocolBinarySubprotocol(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		c, err := websocket.Accept(writer, request, &websocket.AcceptOptions{Subprotocols: []string{binarySubprotocol}})
+		require.NoError(t, err)
+		require.Equal(t, binarySubprotocol, c.Subprotocol())
+
+		messageType, b, err := c.Read(context.TODO())
+		require.NoError(t, err)
+		require.Equal(t, websocket.MessageBinary, messageType)
+
+		var ping cloudevents.Event
+		require.NoError(t, binaryFormat{}.Unmarshal(b, &ping))
+		AssertEvent(t, ping, HasId("1"), HasType("ping"))
+
+		pong := ping.Clone()
+		pong.SetID("2")
+		pong.SetType("pong")
+		b, err = binaryFormat{}.Marshal(&pong)
+		require.NoError(t, err)
+		require.NoError(t, c.Write(context.TODO(), websocket.MessageBinary, b))
+
+		ctx := c.CloseRead(context.TODO())
+		<-ctx.Done()
+	}))
+	defer server.Close()
+
+	p, err := Dial(context.TODO(), server.URL, nil)
+	require.NoError(t, err)
+	require.Equal(t, binaryFormat{}, p.format)
+
+	ping := pingEvent()
+	require.NoError(t, p.Send(context.TODO(), binding.ToMessage(&ping)))
+
+	receivedMessage, err := p.Receive(context.TODO())
+	require.NoError(t, err)
+	pong, err := binding.ToEvent(context.TODO(), receivedMessage)
+	require.NoError(t, err)
+	AssertEvent(t, *pong, HasId("2"), HasType("pong"))
+
+	require.NoError(t, p.Close(context.TODO()))
+}
@@ -7,21 +7,87 @@ package v2
 
 import (
 	"fmt"
+	"sync"
 
 	"nhooyr.io/websocket"
 
 	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/event"
 )
 
-const JsonSubprotocol = "cloudevents.json"
+const (
+	JsonSubprotocol = "cloudevents.json"
+	// ProtobufSubprotocol requires the "application/cloudevents+protobuf" format, which is
+	// registered by importing github.com/cloudevents/sdk-go/binding/format/protobuf/v2.
+	ProtobufSubprotocol = "cloudevents.protobuf"
+)
+
+// Subprotocol describes how the events are encoded in the messages of a WebSocket subprotocol.
+type Subprotocol struct {
+	// MediaType of the event format, resolved with format.Lookup when a connection
+	// negotiates the subprotocol.
+	MediaType string
+	// MessageType of the WebSocket messages, text or binary.
+	MessageType websocket.MessageType
+}
 
+// SupportedSubprotocols is the JSON subprotocol. It's never modified.
+//
+// Deprecated: use Subprotocols, which includes the protobuf subprotocol and the ones registered with AddSubprotocol.
 var SupportedSubprotocols = []string{JsonSubprotocol}
 
+var (
+	subprotocolsMu sync.RWMutex
+	// supportedSubprotocols are the names of the subprotocols, in order of preference.
+	supportedSubprotocols = []string{JsonSubprotocol, ProtobufSubprotocol}
+	subprotocols          = map[string]Subprotocol{
+		JsonSubprotocol:     {MediaType: event.ApplicationCloudEventsJSON, MessageType: websocket.MessageText},
+		ProtobufSubprotocol: {MediaType: "application/cloudevents+protobuf", MessageType: websocket.MessageBinary},
+	}
+)
+
+// Subprotocols returns the supported subprotocols, offered by Dial in order of preference.
+// The subprotocols whose format isn't registered are not offered.
+func Subprotocols() []string {
+	subprotocolsMu.RLock()
+	defer subprotocolsMu.RUnlock()
+	return append([]string(nil), supportedSubprotocols...)
+}
+
+// AddSubprotocol registers the subprotocol name, replacing any existing subprotocol with the same name.
+// A new subprotocol is appended to the supported subprotocols.
+func AddSubprotocol(name string, s Subprotocol) {
+	subprotocolsMu.Lock()
+	defer subprotocolsMu.Unlock()
+	if _, ok := subprotocols[name]; !ok {
+		supportedSubprotocols = append(supportedSubprotocols, name)
+	}
+	subprotocols[name] = s
+}
+
+// availableSubprotocols returns the supported subprotocols whose format is registered.
+func availableSubprotocols() []string {
+	subprotocolsMu.RLock()
+	defer subprotocolsMu.RUnlock()
+	var available []string
+	for _, name := range supportedSubprotocols {
+		if s, ok := subprotocols[name]; ok && format.Lookup(s.MediaType) != nil {
+			available = append(available, name)
+		}
+	}
+	return available
+}
+
 func resolveFormat(subprotocol string) (format.Format, websocket.MessageType, error) {
-	switch subprotocol {
-	case "cloudevents.json":
-		return format.JSON, websocket.MessageText, nil
-	default:
+	subprotocolsMu.RLock()
+	s, ok := subprotocols[subprotocol]
+	subprotocolsMu.RUnlock()
+	if !ok {
 		return nil, websocket.MessageText, fmt.Errorf("subprotocol not supported: %s", subprotocol)
 	}
+	f := format.Lookup(s.MediaType)
+	if f == nil {
+		return nil, s.MessageType, fmt.Errorf("subprotocol %s: format not registered: %s", subprotocol, s.MediaType)
+	}
+	return f, s.MessageType, nil
 }
@@ -12,6 +12,7 @@ import (
 	"nhooyr.io/websocket"
 
 	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/event"
 )
 
 func TestResolveFormat(t *testing.T) {
@@ -39,4 +40,38 @@ func TestResolveFormat(t *testing.T) {
 func TestResolveFormatError(t *testing.T) {
 	_, _, err := resolveFormat("lalala")
 	require.Error(t, err, "subprotocol not supported: lalala")
+
+	// The protobuf format module isn't imported
+	_, _, err = resolveFormat(ProtobufSubprotocol)
+	require.ErrorContains(t, err, "format not registered: application/cloudevents+protobuf")
+	require.NotContains(t, availableSubprotocols(), ProtobufSubprotocol)
+}
+
+// binaryFormat is the JSON format with another media type, to test binary subprotocols.
+type binaryFormat struct{}
+
+func (binaryFormat) MediaType() string                        { return "application/cloudevents+test" }
+func (binaryFormat) Marshal(e *event.Event) ([]byte, error)   { return format.JSON.Marshal(e) }
+func (binaryFormat) Unmarshal(b []byte, e *event.Event) error { return format.JSON.Unmarshal(b, e) }
+
+const binarySubprotocol = "cloudevents.test"
+
+func init() {
+	format.Add(binaryFormat{})
+	AddSubprotocol(binarySubprotocol, Subprotocol{MediaType: binaryFormat{}.MediaType(), MessageType: websocket.MessageBinary})
+}
+
+func TestAddSubprotocol(t *testing.T) {
+	f, messageType, err := resolveFormat(binarySubprotocol)
+	require.NoError(t, err)
+	require.Equal(t, binaryFormat{}, f)
+	require.Equal(t, websocket.MessageBinary, messageType)
+	require.Equal(t, []string{JsonSubprotocol, binarySubprotocol}, availableSubprotocols())
+
+	// The returned subprotocols are a copy
+	supported := Subprotocols()
+	require.Equal(t, []string{JsonSubprotocol, ProtobufSubprotocol, binarySubprotocol}, supported)
+	supported[0] = "modified"
+	require.Equal(t, JsonSubprotocol, Subprotocols()[0])
+	require.Equal(t, []string{JsonSubprotocol}, SupportedSubprotocols)
 }
@@ -9,14 +9,19 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 )
 
 func main() {
 	ctx := context.Background()
+	// Only accept the requests with the bearer token set in $TOKEN.
+	tokens := cehttp.NewKeyRing([]byte(os.Getenv("TOKEN")))
 	p, err := cloudevents.NewHTTP(
 		cloudevents.WithDefaultOptionsHandlerFunc([]string{"POST", "OPTIONS"}, 100, []string{"http://localhost:8181"}, true),
+		cloudevents.WithMiddleware(cehttp.BearerTokenAuth(tokens)),
 	)
 	if err != nil {
 		log.Fatalf("failed to create protocol: %s", err.Error())
@@ -40,5 +45,5 @@ func receive(ctx context.Context, event cloudevents.Event) {
 //
 // cd ./tools; PORT=8181 go run ./http/raw/
 //
-// curl http://localhost:8080 -v -X OPTIONS -H "Origin: http://example.com" -H "WebHook-Request-Origin: http://example.com" -H "WebHook-Request-Callback: http://localhost:8181/do-this?now=true"
+// curl http://localhost:8080 -v -X OPTIONS -H "Authorization: Bearer $TOKEN" -H "Origin: http://example.com" -H "WebHook-Request-Origin: http://example.com" -H "WebHook-Request-Callback: http://localhost:8181/do-this?now=true"
 //
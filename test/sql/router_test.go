@@ -0,0 +1,45 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	cesql "github.com/cloudevents/sdk-go/sql/v2/parser"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+func TestRouterHandleExpression(t *testing.T) {
+	expression, err := cesql.Parse("type LIKE 'com.example.%' AND branch = 'master'")
+	if err != nil {
+		t.Fatalf("failed to parse expression: %s", err)
+	}
+
+	matched := false
+	r := client.NewRouter()
+	r.HandleExpression(expression, func(ctx context.Context, e cloudevents.Event) (*cloudevents.Event, protocol.Result) {
+		matched = true
+		return nil, nil
+	})
+
+	evt := cloudevents.NewEvent("1.0")
+	evt.SetID("evt-1")
+	evt.SetType("com.example.push")
+	evt.SetSource("/event")
+	evt.SetExtension("branch", "master")
+	if result := r.Receive(context.Background(), evt); !protocol.IsACK(result) || !matched {
+		t.Errorf("expected the expression to match, got result %v", result)
+	}
+
+	matched = false
+	evt.SetExtension("branch", "dev")
+	if result := r.Receive(context.Background(), evt); protocol.IsACK(result) || matched {
+		t.Errorf("expected the expression not to match, got result %v", result)
+	}
+}
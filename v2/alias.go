@@ -24,6 +24,8 @@ import (
 type ClientOption = client.Option
 type Client = client.Client
 
+type BatchClient = client.BatchClient
+
 // Event
 
 type Event = event.Event
@@ -18,6 +18,7 @@ type eventFormatKey int
 
 const (
 	formatEventStructured eventFormatKey = iota
+	formatBatch
 )
 
 // EventMessage type-converts a event.Event object to implement Message.
@@ -108,3 +109,13 @@ var _ MessageMetadataReader = (*EventMessage)(nil) // Test it conforms to the in
 func UseFormatForEvent(ctx context.Context, f format.Format) context.Context {
 	return context.WithValue(ctx, formatEventStructured, f)
 }
+
+// UseFormatForBatch configures which format to use when marshalling a batch of events
+func UseFormatForBatch(ctx context.Context, f format.BatchFormat) context.Context {
+	return context.WithValue(ctx, formatBatch, f)
+}
+
+// GetBatchFormat returns the format configured with UseFormatForBatch, or format.JSONBatch
+func GetBatchFormat(ctx context.Context) format.BatchFormat {
+	return GetOrDefaultFromCtx(ctx, formatBatch, format.JSONBatch).(format.BatchFormat)
+}
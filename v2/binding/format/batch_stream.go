@@ -0,0 +1,101 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package format
+
+import (
+	"fmt"
+	"io"
+	"iter"
+
+	jsoniter "github.com/json-iterator/go"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+)
+
+// batchStreamBufferSize is the initial size of the buffer of the iterator, which only grows
+// to hold the largest event of the batch.
+const batchStreamBufferSize = 4096
+
+// DecodeBatchStream iterates over the events of the "application/cloudevents-batch+json" batch read from r.
+//
+// The events are read one at a time, so the memory used is bounded by the size of the largest
+// event, not by the size of the batch. An event which can't be decoded yields an error wrapping
+// the error of the event, and the iteration continues with the next event. The iteration ends after
+// yielding an error if the batch isn't a well-formed JSON array.
+//
+// A null batch is empty. Only whitespace can follow the batch.
+func DecodeBatchStream(r io.Reader) iter.Seq2[event.Event, error] {
+	return func(yield func(event.Event, error) bool) {
+		it := jsoniter.Parse(jsoniter.ConfigFastest, r, batchStreamBufferSize)
+		switch next := it.WhatIsNext(); next {
+		case jsoniter.ArrayValue:
+		case jsoniter.NilValue:
+			it.ReadNil()
+			if err := endOfBatch(it); err != nil {
+				yield(event.Event{}, err)
+			}
+			return
+		default:
+			if it.Error == nil {
+				it.Error = fmt.Errorf("expected a JSON array of events, got %s", jsonValueType(next))
+			}
+			yield(event.Event{}, it.Error)
+			return
+		}
+		for i := 0; it.ReadArray(); i++ {
+			// Capture the raw event, so an invalid event doesn't prevent reading the next ones
+			b := it.SkipAndReturnBytes()
+			if it.Error != nil {
+				break
+			}
+			var e event.Event
+			if err := e.UnmarshalJSON(b); err != nil {
+				if !yield(event.Event{}, fmt.Errorf("failed to decode event %d: %w", i, err)) {
+					return
+				}
+				continue
+			}
+			if !yield(e, nil) {
+				return
+			}
+		}
+		if it.Error != nil {
+			yield(event.Event{}, it.Error)
+			return
+		}
+		if err := endOfBatch(it); err != nil {
+			yield(event.Event{}, err)
+		}
+	}
+}
+
+// endOfBatch checks that only whitespace follows the batch.
+func endOfBatch(it *jsoniter.Iterator) error {
+	it.WhatIsNext()
+	switch it.Error {
+	case io.EOF:
+		return nil
+	case nil:
+		return fmt.Errorf("unexpected data after the JSON array of events")
+	default:
+		return it.Error
+	}
+}
+
+func jsonValueType(t jsoniter.ValueType) string {
+	switch t {
+	case jsoniter.StringValue:
+		return "a string"
+	case jsoniter.NumberValue:
+		return "a number"
+	case jsoniter.BoolValue:
+		return "a boolean"
+	case jsoniter.ObjectValue:
+		return "an object"
+	default:
+		return "an invalid value"
+	}
+}
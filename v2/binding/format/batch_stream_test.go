@@ -0,0 +1,176 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package format_test
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+)
+
+// batchReader generates a JSON batch of n events, counting the bytes read.
+type batchReader struct {
+	n, next int
+	pending string
+	read    int
+}
+
+func (r *batchReader) Read(p []byte) (int, error) {
+	if r.pending == "" {
+		switch {
+		case r.next > r.n:
+			return 0, io.EOF
+		case r.next == r.n:
+			r.pending = "]"
+		default:
+			if r.next == 0 {
+				r.pending = "["
+			} else {
+				r.pending = ","
+			}
+			r.pending += fmt.Sprintf(`{"specversion":"1.0","id":"%d","source":"source","type":"type","data":{"n":%d}}`, r.next, r.next)
+		}
+		r.next++
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	r.read += n
+	return n, nil
+}
+
+func TestDecodeBatchStream(t *testing.T) {
+	const n = 10000
+	r := &batchReader{n: n}
+	i := 0
+	for e, err := range format.DecodeBatchStream(r) {
+		require.NoError(t, err)
+		require.Equal(t, fmt.Sprint(i), e.ID())
+		require.JSONEq(t, fmt.Sprintf(`{"n":%d}`, i), string(e.Data()))
+		if i == 0 {
+			// Only the beginning of the batch is read to decode the first event
+			require.Less(t, r.read, 8*1024)
+		}
+		i++
+	}
+	require.Equal(t, n, i)
+}
+
+func TestDecodeBatchStream_errors(t *testing.T) {
+	type result struct {
+		id  string
+		err string
+	}
+	tests := map[string]struct {
+		batch string
+		want  []result
+	}{
+		"empty": {
+			batch: `[]`,
+		},
+		"null": {
+			batch: `null`,
+		},
+		"invalid event": {
+			batch: `[{"specversion":"1.0","id":"1","source":"source","type":"type"},{"specversion":"0.1"},1,{"specversion":"1.0","id":"2","source":"source","type":"type"}]`,
+			want: []result{
+				{id: "1"},
+				{err: "failed to decode event 1: specversion: unknown value"},
+				{err: "failed to decode event 2"},
+				{id: "2"},
+			},
+		},
+		"not an array": {
+			batch: `{"specversion":"1.0"}`,
+			want:  []result{{err: "expected a JSON array of events, got an object"}},
+		},
+		"truncated": {
+			batch: `[{"specversion":"1.0","id":"1","source":"source","type":"type"},{"specversion":`,
+			want:  []result{{id: "1"}, {err: "Skip"}},
+		},
+		"trailing whitespace": {
+			batch: "[{\"specversion\":\"1.0\",\"id\":\"1\",\"source\":\"source\",\"type\":\"type\"}] \r\n\t",
+			want:  []result{{id: "1"}},
+		},
+		"trailing data": {
+			batch: `[{"specversion":"1.0","id":"1","source":"source","type":"type"}] garbage`,
+			want:  []result{{id: "1"}, {err: "unexpected data after the JSON array of events"}},
+		},
+		"trailing array": {
+			batch: `[][]`,
+			want:  []result{{err: "unexpected data after the JSON array of events"}},
+		},
+		"trailing data after null": {
+			batch: `null 1`,
+			want:  []result{{err: "unexpected data after the JSON array of events"}},
+		},
+		"syntax error": {
+			batch: `[{"specversion":"1.0","id":"1","source":"source","type":"type"} {}]`,
+			want:  []result{{id: "1"}, {err: "ReadArray"}},
+		},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var got []result
+			for e, err := range format.DecodeBatchStream(strings.NewReader(tt.batch)) {
+				if err != nil {
+					got = append(got, result{err: err.Error()})
+				} else {
+					got = append(got, result{id: e.ID()})
+				}
+			}
+			require.Len(t, got, len(tt.want))
+			for i, want := range tt.want {
+				require.Equal(t, want.id, got[i].id)
+				require.Contains(t, got[i].err, want.err)
+			}
+		})
+	}
+}
+
+func TestDecodeBatchStream_largeEvents(t *testing.T) {
+	// The events are larger than the buffer of the iterator, and are read one byte at a time
+	data := strings.Repeat("a", 3*4096)
+	var events []string
+	for i := 0; i < 3; i++ {
+		events = append(events, fmt.Sprintf(`{"specversion":"1.0","id":"%d","source":"source","type":"type","data":"%s"}`, i, data))
+	}
+	batch := " [" + strings.Join(events, " ,\n") + "] \n"
+
+	var ids []string
+	for e, err := range format.DecodeBatchStream(iotest.OneByteReader(strings.NewReader(batch))) {
+		require.NoError(t, err)
+		require.Equal(t, `"`+data+`"`, string(e.Data()))
+		ids = append(ids, e.ID())
+	}
+	require.Equal(t, []string{"0", "1", "2"}, ids)
+
+	var errs []error
+	for _, err := range format.DecodeBatchStream(iotest.OneByteReader(strings.NewReader(batch + "x"))) {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "unexpected data after the JSON array of events")
+}
+
+func TestDecodeBatchStream_break(t *testing.T) {
+	var ids []string
+	for e, err := range format.DecodeBatchStream(&batchReader{n: 10}) {
+		require.NoError(t, err)
+		ids = append(ids, e.ID())
+		if len(ids) == 2 {
+			break
+		}
+	}
+	require.Equal(t, []string{"0", "1"}, ids)
+}
@@ -6,7 +6,7 @@
 /*
 Package format formats structured events.
 
-The "application/cloudevents+json" format is built-in and always
-available. Other formats may be added.
+The "application/cloudevents+json" and "application/cloudevents+xml" formats
+are built-in and always available. Other formats may be added.
 */
 package format
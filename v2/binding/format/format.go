@@ -9,6 +9,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"iter"
 	"strings"
 
 	"github.com/cloudevents/sdk-go/v2/event"
@@ -24,6 +26,20 @@ type Format interface {
 	Unmarshal([]byte, *event.Event) error
 }
 
+// BatchFormat is a Format for batches of events, which can't marshal or unmarshal a single event.
+// Use LookupBatch to find the BatchFormat of a media type.
+type BatchFormat interface {
+	Format
+	// MarshalBatch marshals events to bytes
+	MarshalBatch([]event.Event) ([]byte, error)
+	// UnmarshalBatch unmarshals bytes to events
+	UnmarshalBatch([]byte) ([]event.Event, error)
+	// DecodeBatch iterates over the events read from r, without reading the whole batch in memory
+	// when the format allows it. The iteration stops after an error reading the batch, but it may
+	// continue after an error decoding a single event.
+	DecodeBatch(r io.Reader) iter.Seq2[event.Event, error]
+}
+
 // Prefix for event-format media types.
 const Prefix = "application/cloudevents"
 
@@ -51,17 +67,38 @@ func (jb jsonBatchFmt) MediaType() string {
 	return event.ApplicationCloudEventsBatchJSON
 }
 
-// Marshal will return an error for jsonBatchFmt since the Format interface doesn't support batch Marshalling, and we
-// know it's structured batch json, we'll go direct to the json.UnMarshall() (see `ToEvents()`) since that is the best
-// way to support batch operations for now.
+// Marshal will return an error for jsonBatchFmt, use MarshalBatch instead.
 func (jb jsonBatchFmt) Marshal(e *event.Event) ([]byte, error) {
 	return nil, errors.New("not supported for batch events")
 }
 
+// Unmarshal will return an error for jsonBatchFmt, use UnmarshalBatch instead.
 func (jb jsonBatchFmt) Unmarshal(b []byte, e *event.Event) error {
 	return errors.New("not supported for batch events")
 }
 
+// MarshalBatch marshals the events as a JSON array.
+func (jb jsonBatchFmt) MarshalBatch(events []event.Event) ([]byte, error) {
+	if events == nil {
+		events = []event.Event{}
+	}
+	return json.Marshal(events)
+}
+
+// UnmarshalBatch unmarshals the events of a JSON array.
+func (jb jsonBatchFmt) UnmarshalBatch(b []byte) ([]event.Event, error) {
+	var events []event.Event
+	if err := json.Unmarshal(b, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// DecodeBatch decodes the elements of the JSON array one at a time, see DecodeBatchStream.
+func (jb jsonBatchFmt) DecodeBatch(r io.Reader) iter.Seq2[event.Event, error] {
+	return DecodeBatchStream(r)
+}
+
 // built-in formats
 var formats map[string]Format
 
@@ -69,6 +106,8 @@ func init() {
 	formats = map[string]Format{}
 	Add(JSON)
 	Add(JSONBatch)
+	Add(XML)
+	Add(XMLBatch)
 }
 
 // Lookup returns the format for contentType, or nil if not found.
@@ -81,6 +120,13 @@ func Lookup(contentType string) Format {
 	return formats[contentType]
 }
 
+// LookupBatch returns the batch format for contentType, or nil if not found
+// or if the format doesn't support batches.
+func LookupBatch(contentType string) BatchFormat {
+	f, _ := Lookup(contentType).(BatchFormat)
+	return f
+}
+
 func unknown(mediaType string) error {
 	return fmt.Errorf("unknown event format media-type %#v", mediaType)
 }
@@ -103,3 +149,8 @@ func Unmarshal(mediaType string, b []byte, e *event.Event) error {
 	}
 	return unknown(mediaType)
 }
+
+var (
+	_ BatchFormat = JSONBatch // Test it conforms to the interface
+	_ BatchFormat = XMLBatch  // Test it conforms to the interface
+)
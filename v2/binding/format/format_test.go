@@ -6,7 +6,9 @@
 package format_test
 
 import (
+	"bytes"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -98,6 +100,56 @@ func TestMarshalUnmarshal(t *testing.T) {
 	require.EqualError(err, "unknown event format media-type \"nosuchformat\"")
 }
 
+func TestJSONBatch(t *testing.T) {
+	require := require.New(t)
+	require.Equal(format.JSONBatch, format.LookupBatch("application/cloudevents-batch+json; charset=utf-8"))
+	require.Nil(format.LookupBatch(event.ApplicationCloudEventsJSON))
+
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("source")
+	e.SetType("type")
+	require.NoError(e.SetData(event.ApplicationJSON, "foo"))
+	e2 := e.Clone()
+	e2.SetID("id2")
+	events := []event.Event{e, e2}
+
+	b, err := format.JSONBatch.MarshalBatch(events)
+	require.NoError(err)
+	got, err := format.JSONBatch.UnmarshalBatch(b)
+	require.NoError(err)
+	require.Equal(events, got)
+
+	var decoded []event.Event
+	for e, err := range format.JSONBatch.DecodeBatch(bytes.NewReader(b)) {
+		require.NoError(err)
+		decoded = append(decoded, e)
+		break
+	}
+	require.Equal(events[:1], decoded)
+
+	b, err = format.JSONBatch.MarshalBatch(nil)
+	require.NoError(err)
+	require.Equal("[]", string(b))
+
+	for name, batch := range map[string]string{
+		"not an array":  `{"id":"id"}`,
+		"invalid event": `[{"specversion":"0.1"}]`,
+		"truncated":     `[{"specversion":"1.0","id":"id","source":"source","type":"type"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var n int
+			for _, err := range format.JSONBatch.DecodeBatch(strings.NewReader(batch)) {
+				n++
+				if err != nil {
+					return
+				}
+			}
+			t.Fatalf("no error after %d events", n)
+		})
+	}
+}
+
 type dummyFormat struct{}
 
 func (dummyFormat) MediaType() string                    { return "dummy" }
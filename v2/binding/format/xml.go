@@ -0,0 +1,455 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package format
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	// XMLNamespace is the namespace of the elements of the CloudEvents XML format.
+	XMLNamespace = "http://cloudevents.io/xmlformat/V1"
+
+	xsNamespace  = "http://www.w3.org/2001/XMLSchema"
+	xsiNamespace = "http://www.w3.org/2001/XMLSchema-instance"
+
+	xsBoolean  = "xs:boolean"
+	xsInt      = "xs:int"
+	xsString   = "xs:string"
+	xsBinary   = "xs:base64Binary"
+	xsURI      = "xs:anyURI"
+	xsDateTime = "xs:dateTime"
+	xsAny      = "xs:any"
+)
+
+// XML is the built-in "application/cloudevents+xml" format.
+var XML = xmlFmt{}
+
+type xmlFmt struct{}
+
+func (xmlFmt) MediaType() string { return event.ApplicationCloudEventsXML }
+
+// Marshal writes the event as an <event> element. The attributes which are not strings are
+// typed with xsi:type. The data is written as text, or as base64 if it is binary or can't be
+// represented as XML characters.
+func (xmlFmt) Marshal(e *event.Event) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := writeXMLEvent(&buf, e, true); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Unmarshal reads an <event> element. The data typed xs:any is read as the raw XML
+// it contains.
+func (xmlFmt) Unmarshal(b []byte, e *event.Event) error {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	start, err := xmlRoot(d, "event")
+	if err != nil {
+		return err
+	}
+	e2, err := readXMLEvent(d, start)
+	if err != nil {
+		return err
+	}
+	*e = *e2
+	return nil
+}
+
+// XMLBatch is the built-in "application/cloudevents-batch+xml" format.
+var XMLBatch = xmlBatchFmt{}
+
+type xmlBatchFmt struct{}
+
+func (xmlBatchFmt) MediaType() string { return event.ApplicationCloudEventsBatchXML }
+
+// Marshal will return an error for xmlBatchFmt, use MarshalBatch instead.
+func (xmlBatchFmt) Marshal(e *event.Event) ([]byte, error) {
+	return nil, errors.New("not supported for batch events")
+}
+
+// Unmarshal will return an error for xmlBatchFmt, use UnmarshalBatch instead.
+func (xmlBatchFmt) Unmarshal(b []byte, e *event.Event) error {
+	return errors.New("not supported for batch events")
+}
+
+// MarshalBatch writes the events as <event> elements of a <batch> element.
+func (xmlBatchFmt) MarshalBatch(events []event.Event) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<batch`)
+	writeXMLNamespaces(&buf)
+	buf.WriteString(`>`)
+	for i := range events {
+		if err := writeXMLEvent(&buf, &events[i], false); err != nil {
+			return nil, fmt.Errorf("failed to marshal event %d: %w", i, err)
+		}
+	}
+	buf.WriteString(`</batch>`)
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBatch reads the events of a <batch> element.
+func (f xmlBatchFmt) UnmarshalBatch(b []byte) ([]event.Event, error) {
+	events := []event.Event{}
+	for e, err := range f.DecodeBatch(bytes.NewReader(b)) {
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
+}
+
+// DecodeBatch reads the events of a <batch> element one at a time.
+func (xmlBatchFmt) DecodeBatch(r io.Reader) iter.Seq2[event.Event, error] {
+	return func(yield func(event.Event, error) bool) {
+		d := xml.NewDecoder(r)
+		if _, err := xmlRoot(d, "batch"); err != nil {
+			yield(event.Event{}, err)
+			return
+		}
+		for i := 0; ; i++ {
+			start, err := nextXMLElement(d)
+			if err != nil {
+				yield(event.Event{}, err)
+				return
+			}
+			if start == nil {
+				return
+			}
+			if start.Name.Local != "event" {
+				yield(event.Event{}, fmt.Errorf("unexpected element <%s> in batch", start.Name.Local))
+				return
+			}
+			e, err := readXMLEvent(d, *start)
+			if err != nil {
+				yield(event.Event{}, fmt.Errorf("failed to unmarshal event %d: %w", i, err))
+				return
+			}
+			if !yield(*e, nil) {
+				return
+			}
+		}
+	}
+}
+
+func writeXMLNamespaces(buf *bytes.Buffer) {
+	fmt.Fprintf(buf, ` xmlns=%q xmlns:xs=%q xmlns:xsi=%q`, XMLNamespace, xsNamespace, xsiNamespace)
+}
+
+func writeXMLEvent(buf *bytes.Buffer, e *event.Event, root bool) error {
+	version := spec.VS.Version(e.SpecVersion())
+	if version == nil {
+		return fmt.Errorf("unsupported spec version %q", e.SpecVersion())
+	}
+	buf.WriteString(`<event`)
+	if root {
+		writeXMLNamespaces(buf)
+	}
+	fmt.Fprintf(buf, ` specversion="%s">`, xmlEscape(e.SpecVersion()))
+
+	for _, a := range version.Attributes() {
+		if a.Kind() == spec.SpecVersion {
+			continue
+		}
+		if v := a.Get(e.Context); v != nil {
+			var xsType string
+			switch a.Kind() {
+			case spec.Source, spec.DataSchema:
+				xsType = xsURI
+			case spec.Time:
+				xsType = xsDateTime
+			}
+			s, err := types.Format(v)
+			if err != nil {
+				return fmt.Errorf("failed to encode attribute %s: %w", a.Name(), err)
+			}
+			writeXMLElement(buf, a.Name(), xsType, s)
+		}
+	}
+	extensions := e.Extensions()
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeXMLAttribute(buf, name, extensions[name]); err != nil {
+			return err
+		}
+	}
+
+	if e.DataEncoded != nil {
+		switch {
+		case e.DataBase64 || !isXMLText(e.DataEncoded):
+			writeXMLElement(buf, "data", xsBinary, base64.StdEncoding.EncodeToString(e.DataEncoded))
+		case isXMLContentType(e.DataContentType()) && isXMLFragment(e.DataEncoded):
+			// The XML data is embedded as is, so it's read back as the same document
+			buf.WriteString(`<data xsi:type="` + xsAny + `">`)
+			buf.Write(e.DataEncoded)
+			buf.WriteString(`</data>`)
+		default:
+			writeXMLElement(buf, "data", xsString, string(e.DataEncoded))
+		}
+	}
+	buf.WriteString(`</event>`)
+	return nil
+}
+
+func writeXMLAttribute(buf *bytes.Buffer, name string, v interface{}) error {
+	vv, err := types.Validate(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode attribute %s: %w", name, err)
+	}
+	var xsType string
+	switch vv.(type) {
+	case bool:
+		xsType = xsBoolean
+	case int32:
+		xsType = xsInt
+	case []byte:
+		xsType = xsBinary
+	case types.URI, types.URIRef:
+		xsType = xsURI
+	case types.Timestamp:
+		xsType = xsDateTime
+	}
+	s, err := types.Format(vv)
+	if err != nil {
+		return fmt.Errorf("failed to encode attribute %s: %w", name, err)
+	}
+	writeXMLElement(buf, name, xsType, s)
+	return nil
+}
+
+// writeXMLElement writes a text element, typed with xsType unless it's empty.
+func writeXMLElement(buf *bytes.Buffer, name, xsType, text string) {
+	buf.WriteString(`<` + name)
+	if xsType != "" {
+		buf.WriteString(` xsi:type="` + xsType + `"`)
+	}
+	buf.WriteString(`>` + xmlEscape(text) + `</` + name + `>`)
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// isXMLText returns true if b only contains characters allowed in XML documents.
+// isXMLContentType returns whether the data content type is an XML media type.
+func isXMLContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return mediaType == event.ApplicationXML || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// isXMLFragment returns whether b is well-formed XML content, with at least one element,
+// which can be embedded in the data element and read back unchanged.
+func isXMLFragment(b []byte) bool {
+	if len(b) != len(bytes.TrimSpace(b)) {
+		return false
+	}
+	d := xml.NewDecoder(bytes.NewReader(b))
+	depth, elements := 0, 0
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			return depth == 0 && elements > 0
+		}
+		if err != nil {
+			return false
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			elements++
+		case xml.EndElement:
+			depth--
+		case xml.ProcInst:
+			// The XML declaration isn't allowed within an element
+			if t.Target == "xml" {
+				return false
+			}
+		case xml.Directive:
+			return false
+		}
+	}
+}
+
+func isXMLText(b []byte) bool {
+	if !utf8.Valid(b) {
+		return false
+	}
+	for _, r := range string(b) {
+		switch {
+		case r == '\t' || r == '\n' || r == '\r':
+		case r < 0x20, r >= 0xD800 && r <= 0xDFFF, r == 0xFFFE, r == 0xFFFF:
+			return false
+		}
+	}
+	return true
+}
+
+// xmlRoot returns the root element of the document, which must be named local.
+func xmlRoot(d *xml.Decoder, local string) (xml.StartElement, error) {
+	start, err := nextXMLElement(d)
+	if err != nil {
+		return xml.StartElement{}, err
+	}
+	if start == nil {
+		return xml.StartElement{}, errors.New("empty XML document")
+	}
+	if start.Name.Local != local || (start.Name.Space != "" && start.Name.Space != XMLNamespace) {
+		return xml.StartElement{}, fmt.Errorf("unexpected root element <%s>, want <%s>", start.Name.Local, local)
+	}
+	return *start, nil
+}
+
+// nextXMLElement returns the next child element, or nil at the end of the current element.
+func nextXMLElement(d *xml.Decoder) (*xml.StartElement, error) {
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			return &t, nil
+		case xml.EndElement:
+			return nil, nil
+		}
+	}
+}
+
+// xmlValue is the content of an attribute or data element.
+type xmlValue struct {
+	Text  string `xml:",chardata"`
+	Inner []byte `xml:",innerxml"`
+}
+
+func readXMLEvent(d *xml.Decoder, start xml.StartElement) (*event.Event, error) {
+	var sv string
+	for _, a := range start.Attr {
+		if a.Name.Local == "specversion" {
+			sv = a.Value
+		}
+	}
+	version := spec.VS.Version(sv)
+	if version == nil {
+		return nil, fmt.Errorf("unsupported spec version %q", sv)
+	}
+	e := event.New(sv)
+	for {
+		child, err := nextXMLElement(d)
+		if err != nil {
+			return nil, err
+		}
+		if child == nil {
+			return &e, nil
+		}
+		var v xmlValue
+		if err := d.DecodeElement(&v, child); err != nil {
+			return nil, err
+		}
+		name, xsType := child.Name.Local, xsiType(*child)
+		if name == "data" {
+			if err := setXMLData(&e, xsType, v); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		value, err := xmlAttributeValue(xsType, v.Text)
+		if err == nil && version.Attribute(name) != nil {
+			// The context attributes are set from their string representation
+			value, err = types.Format(value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert attribute %s: %w", name, err)
+		}
+		if err := version.SetAttribute(e.Context, name, value); err != nil {
+			return nil, fmt.Errorf("failed to convert attribute %s: %w", name, err)
+		}
+	}
+}
+
+// xsiType returns the xsi:type of the element, with the "xs:" prefix.
+func xsiType(start xml.StartElement) string {
+	for _, a := range start.Attr {
+		if a.Name.Local == "type" && (a.Name.Space == xsiNamespace || a.Name.Space == "xsi") {
+			if i := strings.IndexByte(a.Value, ':'); i != -1 {
+				return "xs:" + a.Value[i+1:]
+			}
+			return "xs:" + a.Value
+		}
+	}
+	return ""
+}
+
+func xmlAttributeValue(xsType, text string) (interface{}, error) {
+	switch xsType {
+	case "", xsString:
+		return text, nil
+	case xsBoolean:
+		return types.ParseBool(strings.TrimSpace(text))
+	case xsInt:
+		return types.ParseInteger(strings.TrimSpace(text))
+	case xsBinary:
+		return types.ParseBinary(strings.TrimSpace(text))
+	case xsURI:
+		u := types.ParseURIRef(strings.TrimSpace(text))
+		if u == nil {
+			return nil, fmt.Errorf("invalid URI %q", text)
+		}
+		if u.IsAbs() {
+			return types.URI{URL: u.URL}, nil
+		}
+		return *u, nil
+	case xsDateTime:
+		t, err := types.ParseTime(strings.TrimSpace(text))
+		return types.Timestamp{Time: t}, err
+	default:
+		return nil, fmt.Errorf("unsupported xsi:type %q", xsType)
+	}
+}
+
+func setXMLData(e *event.Event, xsType string, v xmlValue) error {
+	switch xsType {
+	case "", xsString:
+		e.DataEncoded = []byte(v.Text)
+	case xsBinary:
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(v.Text))
+		if err != nil {
+			return fmt.Errorf("failed to decode data: %w", err)
+		}
+		e.DataEncoded = b
+		e.DataBase64 = true
+	case xsAny:
+		e.DataEncoded = bytes.TrimSpace(v.Inner)
+	default:
+		return fmt.Errorf("unsupported xsi:type %q for data", xsType)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package format_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding/format"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/test"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+// fullXMLEvent returns test.FullEvent with its absolute URI reference extension typed as a types.URI,
+// as it's read back from an xs:anyURI element.
+func fullXMLEvent() event.Event {
+	e := test.FullEvent()
+	e.SetExtension("exurl", types.URI{URL: test.Source.URL})
+	e.SetExtension("exuriref", types.URIRef{URL: url.URL{Path: "/ref"}})
+	return e
+}
+
+func TestXML(t *testing.T) {
+	require.Equal(t, format.XML, format.Lookup("application/cloudevents+xml; charset=utf-8"))
+	require.Equal(t, format.XMLBatch, format.Lookup(event.ApplicationCloudEventsBatchXML))
+
+	for name, setData := range map[string]func(e *event.Event) error{
+		"no data": func(e *event.Event) error {
+			e.DataEncoded = nil
+			return nil
+		},
+		"json data": func(e *event.Event) error { return nil },
+		"text data": func(e *event.Event) error {
+			return e.SetData(event.TextPlain, " <text> & \r\n ")
+		},
+		"xml data": func(e *event.Event) error {
+			e.SetDataContentType(event.ApplicationXML)
+			e.DataEncoded = []byte(`<order id="1"/>`)
+			return nil
+		},
+		"malformed xml data": func(e *event.Event) error {
+			e.SetDataContentType(event.ApplicationXML)
+			e.DataEncoded = []byte(`<order id="1">`)
+			return nil
+		},
+		"xml document data": func(e *event.Event) error {
+			e.SetDataContentType("application/vnd.order+xml")
+			e.DataEncoded = []byte(`<?xml version="1.0"?><order id="1"/>`)
+			return nil
+		},
+		"binary data": func(e *event.Event) error {
+			return e.SetData("application/octet-stream", []byte{0, 1, 2})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			e := fullXMLEvent()
+			e.SetSubject("a < b & c")
+			require.NoError(t, setData(&e))
+			b, err := format.Marshal(event.ApplicationCloudEventsXML, &e)
+			require.NoError(t, err)
+			var e2 event.Event
+			require.NoError(t, format.Unmarshal(event.ApplicationCloudEventsXML, b, &e2))
+			require.Equal(t, e, e2)
+		})
+	}
+}
+
+func TestXMLMarshal(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("/source")
+	e.SetType("type")
+	e.SetExtension("int", 1)
+	require.NoError(t, e.SetData(event.TextPlain, "a<b"))
+	b, err := format.XML.Marshal(&e)
+	require.NoError(t, err)
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<event xmlns="http://cloudevents.io/xmlformat/V1" xmlns:xs="http://www.w3.org/2001/XMLSchema" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" specversion="1.0">`+
+		`<id>id</id><source xsi:type="xs:anyURI">/source</source><type>type</type><datacontenttype>text/plain</datacontenttype>`+
+		`<int xsi:type="xs:int">1</int><data xsi:type="xs:string">a&lt;b</data></event>`, string(b))
+}
+
+func TestXMLMarshalXMLData(t *testing.T) {
+	e := event.New()
+	e.SetID("id")
+	e.SetSource("/source")
+	e.SetType("type")
+	e.SetDataContentType(event.ApplicationXML)
+	e.DataEncoded = []byte(`<order xmlns="urn:orders"><id>1</id></order>`)
+	b, err := format.XML.Marshal(&e)
+	require.NoError(t, err)
+	require.Contains(t, string(b), `<data xsi:type="xs:any"><order xmlns="urn:orders"><id>1</id></order></data>`)
+}
+
+func TestXMLUnmarshal(t *testing.T) {
+	b := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ce:event xmlns:ce="http://cloudevents.io/xmlformat/V1" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+    xmlns:xsd="http://www.w3.org/2001/XMLSchema" specversion="1.0">
+  <ce:id>1</ce:id>
+  <ce:source xsi:type="xsd:anyURI">http://example.com/source</ce:source>
+  <ce:type>type</ce:type>
+  <ce:time xsi:type="xsd:dateTime">2021-01-01T01:01:01Z</ce:time>
+  <ce:datacontenttype>application/xml</ce:datacontenttype>
+  <ce:ext xsi:type="xsd:boolean">true</ce:ext>
+  <ce:data xsi:type="xsd:any">
+    <order xmlns="urn:orders"><id>1</id></order>
+  </ce:data>
+</ce:event>`)
+	var e event.Event
+	require.NoError(t, format.XML.Unmarshal(b, &e))
+	require.NoError(t, e.Validate())
+	require.Equal(t, "1", e.ID())
+	require.Equal(t, "http://example.com/source", e.Source())
+	require.Equal(t, time.Date(2021, 1, 1, 1, 1, 1, 0, time.UTC), e.Time())
+	require.Equal(t, true, e.Extensions()["ext"])
+	require.Equal(t, `<order xmlns="urn:orders"><id>1</id></order>`, string(e.Data()))
+
+	for name, tt := range map[string]struct {
+		doc     string
+		wantErr string
+	}{
+		"root":        {doc: `<batch/>`, wantErr: "unexpected root element"},
+		"namespace":   {doc: `<event xmlns="urn:other" specversion="1.0"/>`, wantErr: "unexpected root element"},
+		"specversion": {doc: `<event/>`, wantErr: "unsupported spec version"},
+		"type": {
+			doc:     `<event xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" specversion="1.0"><ext xsi:type="xs:double">1</ext></event>`,
+			wantErr: "unsupported xsi:type",
+		},
+		"value": {
+			doc:     `<event xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" specversion="1.0"><ext xsi:type="xs:int">a</ext></event>`,
+			wantErr: "failed to convert attribute ext",
+		},
+		"syntax": {doc: `<event specversion="1.0"><id>`, wantErr: "XML syntax error"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.ErrorContains(t, format.XML.Unmarshal([]byte(tt.doc), &e), tt.wantErr)
+		})
+	}
+}
+
+func TestXMLBatch(t *testing.T) {
+	e1 := fullXMLEvent()
+	require.NoError(t, e1.SetData(event.TextPlain, "text"))
+	e2 := event.New(event.CloudEventsVersionV03)
+	e2.SetID("id")
+	e2.SetSource("source")
+	e2.SetType("type")
+	events := []event.Event{e1, e2}
+
+	b, err := format.XMLBatch.MarshalBatch(events)
+	require.NoError(t, err)
+	got, err := format.XMLBatch.UnmarshalBatch(b)
+	require.NoError(t, err)
+	require.Equal(t, events, got)
+
+	b, err = format.XMLBatch.MarshalBatch(nil)
+	require.NoError(t, err)
+	got, err = format.XMLBatch.UnmarshalBatch(b)
+	require.NoError(t, err)
+	require.Empty(t, got)
+
+	_, err = format.XMLBatch.Marshal(&e1)
+	require.Error(t, err)
+	_, err = format.XMLBatch.UnmarshalBatch([]byte(`<batch><id/></batch>`))
+	require.ErrorContains(t, err, "unexpected element <id>")
+}
@@ -8,6 +8,7 @@ package binding
 import (
 	"context"
 
+	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
 )
 
@@ -130,6 +131,13 @@ type MessageContext interface {
 	Context() context.Context
 }
 
+// BatchMessage interface exposes the format of a message in batch mode (Message.ReadEncoding() == EncodingBatch)
+// Only some Message implementations implement this interface.
+type BatchMessage interface {
+	// Get the format of the batch of events
+	BatchFormat() format.BatchFormat
+}
+
 // MessageWrapper interface is used to walk through a decorated Message and unwrap it.
 type MessageWrapper interface {
 	Message
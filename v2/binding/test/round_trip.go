@@ -0,0 +1,41 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+// RoundTripFunc writes the message m with a binding implementation, and returns the message read back.
+type RoundTripFunc func(t *testing.T, ctx context.Context, m binding.Message) binding.Message
+
+// RunRoundTripTests checks that each of the test events, written in structured and binary mode
+// by roundTrip, is read back unchanged.
+// The extensions of the events are converted to strings, as most bindings don't keep their types.
+func RunRoundTripTests(t *testing.T, ctx context.Context, roundTrip RoundTripFunc) {
+	test.EachEvent(t, test.Events(), func(t *testing.T, e event.Event) {
+		e = test.ConvertEventExtensionsToString(t, e)
+		for name, m := range map[string]binding.Message{
+			"structured": MustCreateMockStructuredMessage(t, e),
+			"binary":     MustCreateMockBinaryMessage(e),
+		} {
+			t.Run(name, func(t *testing.T) {
+				out := roundTrip(t, ctx, m)
+				got, err := binding.ToEvent(ctx, out)
+				require.NoError(t, err)
+				require.NoError(t, out.Finish(nil))
+				test.AssertEventEquals(t, e, *got)
+			})
+		}
+	})
+}
@@ -8,7 +8,6 @@ package binding
 import (
 	"bytes"
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -65,7 +64,45 @@ func ToEvent(ctx context.Context, message MessageReader, transformers ...Transfo
 	return &e, Transformers(transformers).Transform((*EventMessage)(&e), encoder)
 }
 
+// ToStreamingEvent translates a Message with a valid Structured or Binary representation to an Event,
+// like ToEvent, but without reading the data in memory when the Message is in Binary encoding:
+// the data is returned as an io.Reader, and the DataEncoded of the returned Event is empty.
+// The reader is valid until the Message is finished.
+// For the other encodings, the data is already in memory and the reader reads it.
+// transformers can be nil and this function guarantees that they are invoked only once during the encoding process.
+func ToStreamingEvent(ctx context.Context, message MessageReader, transformers ...Transformer) (*event.Event, io.Reader, error) {
+	if message == nil {
+		return nil, nil, nil
+	}
+
+	if message.ReadEncoding() == EncodingBinary {
+		e := event.New()
+		encoder := &streamingEventBuilder{messageToEventBuilder: (*messageToEventBuilder)(&e)}
+		if err := writeBinary(ctx, message, encoder); err != nil {
+			return nil, nil, err
+		}
+		if err := Transformers(transformers).Transform((*EventMessage)(&e), encoder); err != nil {
+			return nil, nil, err
+		}
+		if encoder.data == nil {
+			encoder.data = bytes.NewReader(nil)
+		}
+		return &e, encoder.data, nil
+	}
+
+	e, err := ToEvent(ctx, message, transformers...)
+	if err != nil || e == nil {
+		return nil, nil, err
+	}
+	data := e.Data()
+	e.DataEncoded = nil
+	return e, bytes.NewReader(data), nil
+}
+
 // ToEvents translates a Batch Message and corresponding Reader data to a slice of Events.
+// The body is decoded with the format of the message if it implements BatchMessage,
+// otherwise with format.JSONBatch. To process a large batch without holding all its events in memory,
+// iterate over the events of format.BatchFormat.DecodeBatch instead.
 // This function returns the Events generated from the body data, or an error that points
 // to the conversion issue.
 func ToEvents(ctx context.Context, message MessageReader, body io.Reader) ([]event.Event, error) {
@@ -74,10 +111,18 @@ func ToEvents(ctx context.Context, message MessageReader, body io.Reader) ([]eve
 		return nil, ErrCannotConvertToEvents
 	}
 
-	// Since Format doesn't support batch Marshalling, and we know it's structured batch json, we'll go direct to the
-	// json.UnMarshall(), since that is the best way to support batch operations for now.
-	var events []event.Event
-	return events, json.NewDecoder(body).Decode(&events)
+	var f format.BatchFormat = format.JSONBatch
+	if m, ok := message.(BatchMessage); ok && m.BatchFormat() != nil {
+		f = m.BatchFormat()
+	}
+	events := []event.Event{}
+	for e, err := range f.DecodeBatch(body) {
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, nil
 }
 
 type messageToEventBuilder event.Event
@@ -117,6 +162,17 @@ func (b *messageToEventBuilder) SetData(data io.Reader) error {
 	return nil
 }
 
+// streamingEventBuilder is a messageToEventBuilder keeping the data reader in place of reading it.
+type streamingEventBuilder struct {
+	*messageToEventBuilder
+	data io.Reader
+}
+
+func (b *streamingEventBuilder) SetData(data io.Reader) error {
+	b.data = data
+	return nil
+}
+
 func (b *messageToEventBuilder) SetAttribute(attribute spec.Attribute, value interface{}) error {
 	if value == nil {
 		_ = attribute.Delete(b.Context)
@@ -12,13 +12,13 @@ import (
 	"strings"
 	"testing"
 
-	"github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/stretchr/testify/require"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
 	. "github.com/cloudevents/sdk-go/v2/binding/test"
 	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol/http"
 	. "github.com/cloudevents/sdk-go/v2/test"
 )
 
@@ -66,6 +66,65 @@ func TestToEvent_success(t *testing.T) {
 	})
 }
 
+func TestToStreamingEvent(t *testing.T) {
+	EachEvent(t, Events(), func(t *testing.T, v event.Event) {
+		testCases := []toEventTestCase{
+			{
+				name:    "From mock structured/" + TestNameOf(v),
+				message: MustCreateMockStructuredMessage(t, v),
+				want:    v,
+			},
+			{
+				name:    "From mock binary/" + TestNameOf(v),
+				message: MustCreateMockBinaryMessage(v),
+				want:    v,
+			},
+			{
+				name:  "From event/" + TestNameOf(v),
+				event: v,
+				want:  v,
+			},
+		}
+		for _, tt := range testCases {
+			t.Run(tt.name, func(t *testing.T) {
+				var inputMessage binding.Message
+				if tt.message != nil {
+					inputMessage = tt.message
+				} else {
+					e := tt.event.Clone()
+					inputMessage = binding.ToMessage(&e)
+				}
+				got, data, err := binding.ToStreamingEvent(context.Background(), inputMessage)
+				require.NoError(t, err)
+				require.Nil(t, got.DataEncoded)
+
+				b, err := io.ReadAll(data)
+				require.NoError(t, err)
+				require.Equal(t, string(tt.want.Data()), string(b))
+				got.DataEncoded = tt.want.DataEncoded
+				got.DataBase64 = tt.want.DataBase64
+				AssertEventEquals(t, ConvertEventExtensionsToString(t, tt.want), ConvertEventExtensionsToString(t, *got))
+			})
+		}
+	})
+}
+
+func TestToStreamingEvent_does_not_read_binary_data(t *testing.T) {
+	header := nethttp.Header{}
+	header.Set("ce-specversion", "1.0")
+	header.Set("ce-id", "1")
+	header.Set("ce-type", "unit.test")
+	header.Set("ce-source", "/unit/test")
+	body := io.NopCloser(strings.NewReader("large payload"))
+
+	got, data, err := binding.ToStreamingEvent(context.Background(), http.NewMessage(header, body))
+	require.NoError(t, err)
+	require.Equal(t, "1", got.ID())
+	require.Nil(t, got.DataEncoded)
+	// The body is handed over unread
+	require.Equal(t, body, data)
+}
+
 func TestToEvent_bad_spec_version_binary(t *testing.T) {
 	inputEvent := FullEvent()
 
@@ -184,6 +243,15 @@ func TestToEvents(t *testing.T) {
 				require.ErrorContains(t, err, "specversion: unknown value")
 			},
 		},
+		"xml batch": {
+			jsn:         `<batch xmlns="http://cloudevents.io/xmlformat/V1"><event specversion="1.0"><id>id1</id><source>source</source><type>type</type></event><event specversion="1.0"><id>id2</id><source>source</source><type>type</type></event></batch>`,
+			contentType: event.ApplicationCloudEventsBatchXML,
+			expected: func(t *testing.T, list []event.Event, err error) {
+				require.NoError(t, err)
+				require.Len(t, list, 2)
+				require.Equal(t, "id2", list[1].ID())
+			},
+		},
 		"bad content type": {
 			jsn:         `[{"data":"foo","datacontenttype":"application/json","id":"id","source":"source","specversion":"1.0","type":"type"}]`,
 			contentType: event.ApplicationJSON,
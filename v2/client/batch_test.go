@@ -0,0 +1,279 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+// batchSender records the batches it sends, rejecting the ones containing the event with id reject,
+// or only that event if partial is set.
+type batchSender struct {
+	mu      sync.Mutex
+	batches [][]event.Event
+	reject  string
+	partial bool
+}
+
+func (s *batchSender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) error {
+	e, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	return s.SendBatch(ctx, []event.Event{*e})
+}
+
+func (s *batchSender) SendBatch(_ context.Context, events []event.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, events)
+	if s.partial {
+		results := make([]protocol.Result, len(events))
+		for i, e := range events {
+			results[i] = protocol.ResultACK
+			if e.ID() == s.reject {
+				results[i] = protocol.NewReceipt(false, "rejected event")
+			}
+		}
+		return &protocol.BatchResult{Result: protocol.NewReceipt(false, "partially rejected batch"), Results: results}
+	}
+	for _, e := range events {
+		if e.ID() == s.reject {
+			return protocol.NewReceipt(false, "rejected batch")
+		}
+	}
+	return nil
+}
+
+func (s *batchSender) ids() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids [][]string
+	for _, b := range s.batches {
+		var batch []string
+		for _, e := range b {
+			batch = append(batch, e.ID())
+		}
+		ids = append(ids, batch)
+	}
+	return ids
+}
+
+func batchTestEvents(n int) []event.Event {
+	events := make([]event.Event, n)
+	for i := range events {
+		events[i] = test.MinEvent()
+		events[i].SetID(strconv.Itoa(i))
+	}
+	return events
+}
+
+func TestClientSendBatch(t *testing.T) {
+	s := &batchSender{}
+	c, err := client.New(s)
+	require.NoError(t, err)
+
+	events := batchTestEvents(3)
+	events[1].SetType("") // invalid
+
+	results := client.SendBatch(context.Background(), c, events)
+	require.Len(t, results, 3)
+	require.True(t, protocol.IsACK(results[0]))
+	require.Error(t, results[1])
+	require.False(t, protocol.IsACK(results[1]))
+	require.True(t, protocol.IsACK(results[2]))
+	require.Equal(t, [][]string{{"0", "2"}}, s.ids())
+}
+
+func TestClientSendBatchNACK(t *testing.T) {
+	s := &batchSender{reject: "1"}
+	c, err := client.New(s)
+	require.NoError(t, err)
+
+	// Every event shares the result of the batch
+	results := client.SendBatch(context.Background(), c, batchTestEvents(2))
+	require.Len(t, results, 2)
+	for _, result := range results {
+		require.True(t, protocol.IsNACK(result), "unexpected result %v", result)
+	}
+}
+
+func TestClientSendBatchPartialNACK(t *testing.T) {
+	s := &batchSender{reject: "2", partial: true}
+	c, err := client.New(s)
+	require.NoError(t, err)
+
+	events := batchTestEvents(3)
+	events[0].SetType("") // invalid
+
+	// Every event gets its own result
+	results := client.SendBatch(context.Background(), c, events)
+	require.Len(t, results, 3)
+	require.False(t, protocol.IsACK(results[0]))
+	require.True(t, protocol.IsACK(results[1]), "unexpected result %v", results[1])
+	require.True(t, protocol.IsNACK(results[2]), "unexpected result %v", results[2])
+}
+
+// sendOnlyClient is a client.Client which isn't a client.BatchClient.
+type sendOnlyClient struct {
+	client.Client
+}
+
+func TestSendBatchWithoutBatchClient(t *testing.T) {
+	ch := make(chan binding.Message, 2)
+	c, err := client.New(gochan.Sender(ch))
+	require.NoError(t, err)
+
+	results := client.SendBatch(context.Background(), sendOnlyClient{c}, batchTestEvents(2))
+	require.Len(t, results, 2)
+	for i, result := range results {
+		require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+		e, err := binding.ToEvent(context.Background(), <-ch)
+		require.NoError(t, err)
+		require.Equal(t, strconv.Itoa(i), e.ID())
+	}
+}
+
+func TestClientSendBatchWithoutBatchSender(t *testing.T) {
+	ch := make(chan binding.Message, 2)
+	c, err := client.New(gochan.Sender(ch))
+	require.NoError(t, err)
+
+	results := client.SendBatch(context.Background(), c, batchTestEvents(2))
+	require.Len(t, results, 2)
+	for i, result := range results {
+		require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+		e, err := binding.ToEvent(context.Background(), <-ch)
+		require.NoError(t, err)
+		require.Equal(t, strconv.Itoa(i), e.ID())
+	}
+}
+
+func sendConcurrently(t *testing.T, c client.Client, events []event.Event) []protocol.Result {
+	results := make([]protocol.Result, len(events))
+	var wg sync.WaitGroup
+	for i := range events {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.Send(context.Background(), events[i])
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+func TestBatchingSenderMaxEvents(t *testing.T) {
+	s := &batchSender{}
+	bs := client.NewBatchingSender(s, client.BatchingConfig{MaxEvents: 2, Linger: time.Hour})
+	c, err := client.New(bs)
+	require.NoError(t, err)
+
+	for _, result := range sendConcurrently(t, c, batchTestEvents(4)) {
+		require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+	}
+	batches := s.ids()
+	require.Len(t, batches, 2)
+	for _, b := range batches {
+		require.Len(t, b, 2)
+	}
+	require.NoError(t, bs.Close(context.Background()))
+}
+
+func TestBatchingSenderLinger(t *testing.T) {
+	s := &batchSender{}
+	bs := client.NewBatchingSender(s, client.BatchingConfig{MaxEvents: 100, Linger: 20 * time.Millisecond})
+	c, err := client.New(bs)
+	require.NoError(t, err)
+
+	for _, result := range sendConcurrently(t, c, batchTestEvents(3)) {
+		require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+	}
+	batches := s.ids()
+	count := 0
+	for _, b := range batches {
+		count += len(b)
+	}
+	require.Equal(t, 3, count)
+	require.NoError(t, bs.Close(context.Background()))
+}
+
+func TestBatchingSenderMaxBytes(t *testing.T) {
+	s := &batchSender{}
+	// Each test event is encoded in more than 75 and less than 150 bytes, the last batch is flushed after the linger
+	bs := client.NewBatchingSender(s, client.BatchingConfig{MaxBytes: 150, Linger: 20 * time.Millisecond})
+	c, err := client.New(bs)
+	require.NoError(t, err)
+
+	for _, result := range sendConcurrently(t, c, batchTestEvents(3)) {
+		require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+	}
+	for _, b := range s.ids() {
+		require.Len(t, b, 1)
+	}
+	require.NoError(t, bs.Close(context.Background()))
+}
+
+func TestBatchingSenderResults(t *testing.T) {
+	s := &batchSender{reject: "1"}
+	bs := client.NewBatchingSender(s, client.BatchingConfig{MaxEvents: 2, Linger: time.Hour})
+	c, err := client.New(bs)
+	require.NoError(t, err)
+
+	events := batchTestEvents(2)
+	results := sendConcurrently(t, c, events)
+	for _, result := range results {
+		require.True(t, protocol.IsNACK(result), "unexpected result %v", result)
+	}
+	require.NoError(t, bs.Close(context.Background()))
+}
+
+func TestBatchingSenderPartialResults(t *testing.T) {
+	s := &batchSender{reject: "1", partial: true}
+	bs := client.NewBatchingSender(s, client.BatchingConfig{MaxEvents: 3, Linger: time.Hour})
+	c, err := client.New(bs)
+	require.NoError(t, err)
+
+	events := batchTestEvents(3)
+	results := sendConcurrently(t, c, events)
+	require.Len(t, s.ids(), 1)
+	// Each event gets its own result, whatever its position in the batch
+	for i, result := range results {
+		require.Equal(t, i == 1, protocol.IsNACK(result), "event %d: unexpected result %v", i, result)
+	}
+	require.NoError(t, bs.Close(context.Background()))
+}
+
+func TestBatchingSenderClose(t *testing.T) {
+	s := &batchSender{}
+	bs := client.NewBatchingSender(s, client.BatchingConfig{MaxEvents: 100, Linger: time.Hour})
+	c, err := client.New(bs)
+	require.NoError(t, err)
+
+	// Send returns when ctx is done, but the event stays in the pending batch
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, c.Send(ctx, batchTestEvents(1)[0]), context.Canceled)
+	require.Empty(t, s.ids())
+
+	// The pending batch is flushed by Close
+	require.NoError(t, bs.Close(context.Background()))
+	require.Equal(t, [][]string{{"0"}}, s.ids())
+	require.EqualError(t, c.Send(context.Background(), batchTestEvents(1)[0]), "batching sender is closed")
+}
@@ -0,0 +1,185 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+const (
+	// DefaultBatchMaxEvents is the number of events a BatchingSender flushes at most in a batch,
+	// when BatchingConfig.MaxEvents is not set.
+	DefaultBatchMaxEvents = 100
+	// DefaultBatchLinger is the time a BatchingSender waits for more events before flushing a batch,
+	// when BatchingConfig.Linger is not set.
+	DefaultBatchLinger = 100 * time.Millisecond
+)
+
+// BatchingConfig configures when a BatchingSender flushes the events it buffered.
+// A batch is flushed as soon as any of the limits is reached.
+type BatchingConfig struct {
+	// MaxEvents is the maximum number of events in a batch.
+	// If 0, DefaultBatchMaxEvents is used.
+	MaxEvents int
+	// MaxBytes is the maximum size of a batch, computed on the JSON encoding of its events.
+	// A single event larger than MaxBytes is sent in a batch on its own.
+	// If 0, the size of the batches is not limited.
+	MaxBytes int
+	// Linger is the maximum time an event waits in the batch before it's flushed.
+	// If 0, DefaultBatchLinger is used.
+	Linger time.Duration
+}
+
+// BatchingSender is a protocol.Sender buffering the sent events and flushing them in batches
+// through a protocol.BatchSender, e.g. the HTTP protocol.
+// Send blocks until the batch containing the event has been sent and it returns the result of the event,
+// if reported by the recipient with a protocol.BatchResult, otherwise the result of the whole batch.
+// A batch is sent with the context of its first event, detached from its cancellation.
+//
+// Use it as the protocol of a client to batch the events sent concurrently through Client.Send:
+//
+//	p, _ := cehttp.New(cehttp.WithTarget(target))
+//	s := client.NewBatchingSender(p, client.BatchingConfig{MaxEvents: 50, Linger: 10 * time.Millisecond})
+//	defer s.Close(ctx)
+//	c, _ := client.New(s)
+type BatchingSender struct {
+	sender protocol.BatchSender
+	config BatchingConfig
+
+	mu      sync.Mutex
+	pending *pendingBatch
+	closed  bool
+	// flushing tracks the batches being sent.
+	flushing sync.WaitGroup
+}
+
+type pendingBatch struct {
+	ctx    context.Context
+	events []event.Event
+	size   int
+	timer  *time.Timer
+	done   chan struct{}
+	err    error
+}
+
+var _ protocol.SendCloser = (*BatchingSender)(nil)
+
+// NewBatchingSender returns a BatchingSender flushing the events through sender according to config.
+func NewBatchingSender(sender protocol.BatchSender, config BatchingConfig) *BatchingSender {
+	if config.MaxEvents <= 0 {
+		config.MaxEvents = DefaultBatchMaxEvents
+	}
+	if config.Linger <= 0 {
+		config.Linger = DefaultBatchLinger
+	}
+	return &BatchingSender{sender: sender, config: config}
+}
+
+// Send adds the event read from m to the pending batch and waits for the batch to be sent.
+// If ctx is done before, Send returns the ctx error, but the event is sent anyway.
+func (s *BatchingSender) Send(ctx context.Context, m binding.Message, transformers ...binding.Transformer) (err error) {
+	if ctx == nil {
+		return fmt.Errorf("nil Context")
+	} else if m == nil {
+		return fmt.Errorf("nil Message")
+	}
+	defer func() { _ = m.Finish(err) }()
+
+	e, err := binding.ToEvent(ctx, m, transformers...)
+	if err != nil {
+		return err
+	}
+	size := 0
+	if s.config.MaxBytes > 0 {
+		b, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		// One more byte for the separator in the JSON array
+		size = len(b) + 1
+	}
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errors.New("batching sender is closed")
+	}
+	if s.pending != nil && s.config.MaxBytes > 0 && s.pending.size+size > s.config.MaxBytes {
+		s.flushLocked()
+	}
+	if s.pending == nil {
+		b := &pendingBatch{ctx: context.WithoutCancel(ctx), done: make(chan struct{})}
+		b.timer = time.AfterFunc(s.config.Linger, func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.pending == b {
+				s.flushLocked()
+			}
+		})
+		s.pending = b
+	}
+	b := s.pending
+	index := len(b.events)
+	b.events = append(b.events, *e)
+	b.size += size
+	if len(b.events) >= s.config.MaxEvents || (s.config.MaxBytes > 0 && b.size >= s.config.MaxBytes) {
+		s.flushLocked()
+	}
+	s.mu.Unlock()
+
+	select {
+	case <-b.done:
+		return protocol.BatchEventResult(b.err, index)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close flushes the pending batch and waits for all the batches to be sent, or for ctx to be done.
+// The events sent after Close are rejected.
+func (s *BatchingSender) Close(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	if s.pending != nil {
+		s.flushLocked()
+	}
+	s.mu.Unlock()
+
+	flushed := make(chan struct{})
+	go func() {
+		s.flushing.Wait()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// flushLocked sends the pending batch in background. It must be invoked holding s.mu.
+func (s *BatchingSender) flushLocked() {
+	b := s.pending
+	s.pending = nil
+	b.timer.Stop()
+
+	s.flushing.Add(1)
+	go func() {
+		defer s.flushing.Done()
+		defer close(b.done)
+		b.err = s.sender.SendBatch(b.ctx, b.events)
+	}()
+}
@@ -12,6 +12,7 @@ import (
 	"io"
 	"runtime"
 	"sync"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -47,6 +48,15 @@ type Client interface {
 	// * func(event.Event) (*event.Event, error)
 	// * func(context.Context, event.Event) *event.Event
 	// * func(context.Context, event.Event) (*event.Event, error)
+	// * func(context.Context, event.Event, io.Reader)
+	// * func(context.Context, event.Event, io.Reader) error
+	// * func(context.Context, event.Event, io.Reader) *event.Event
+	// * func(context.Context, event.Event, io.Reader) (*event.Event, error)
+	// When fn takes an io.Reader, the data of the event is streamed from the
+	// reader in place of being read in memory, if the protocol supports it,
+	// and the event has no data. The reader is valid only during the call of fn,
+	// and can be read only once: such fn can not be used along with
+	// WithReceiverRetries or WithDeadLetterSender, which need to read the data again.
 	// The error returned may impact the messages processing made by the protocol
 	// used (example: message acknowledgement). Please refer to each protocol's
 	// package documentation of the function "Finish(err error) error".
@@ -102,6 +112,13 @@ type ceClient struct {
 	pollGoroutines            int
 	blockingCallback          bool
 	ackMalformedEvent         bool
+	deadLetter                *deadLetter
+	retryParams               *cecontext.RetryParams
+	idempotency               *idempotency
+	middlewares               []Middleware
+	maxInFlight               int
+	handlerTimeout            time.Duration
+	drainTimeout              time.Duration
 }
 
 func (c *ceClient) applyOptions(opts ...Option) error {
@@ -132,14 +149,102 @@ func (c *ceClient) Send(ctx context.Context, e event.Event) protocol.Result {
 	if err = e.Validate(); err != nil {
 		return err
 	}
+	return c.send(ctx, e)
+}
+
+// BatchClient is implemented by the clients which can send batches of events,
+// like the clients returned by New.
+type BatchClient interface {
+	Client
+
+	// SendBatch will transmit the given events over the client's configured
+	// transport, returning the result of each event in the same order.
+	// When the transport implements protocol.BatchSender, the valid events are
+	// sent in a single message: each of them gets the result the recipient reported
+	// for it if any, e.g. with a protocol.BatchResult, otherwise the result of the
+	// whole batch. Without protocol.BatchSender they are sent one by one.
+	// The events which fail validation get their own validation error.
+	SendBatch(ctx context.Context, events []event.Event) []protocol.Result
+}
+
+// SendBatch sends the events with c.SendBatch if c is a BatchClient,
+// otherwise with c.Send, one by one.
+func SendBatch(ctx context.Context, c Client, events []event.Event) []protocol.Result {
+	if bc, ok := c.(BatchClient); ok {
+		return bc.SendBatch(ctx, events)
+	}
+	results := make([]protocol.Result, len(events))
+	for i, e := range events {
+		results[i] = c.Send(ctx, e)
+	}
+	return results
+}
 
+var _ BatchClient = (*ceClient)(nil)
+
+// send sends an event which has been already defaulted and validated.
+func (c *ceClient) send(ctx context.Context, e event.Event) error {
 	// Event has been defaulted and validated, record we are going to perform send.
 	ctx, cb := c.observabilityService.RecordSendingEvent(ctx, e)
-	err = c.sender.Send(ctx, (*binding.EventMessage)(&e))
+	err := c.sender.Send(ctx, (*binding.EventMessage)(&e))
 	defer cb(err)
 	return err
 }
 
+func (c *ceClient) SendBatch(ctx context.Context, events []event.Event) []protocol.Result {
+	results := make([]protocol.Result, len(events))
+	if c.sender == nil {
+		err := errors.New("sender not set")
+		for i := range results {
+			results[i] = err
+		}
+		return results
+	}
+
+	for _, f := range c.outboundContextDecorators {
+		ctx = f(ctx)
+	}
+
+	// Default and validate the events, keeping track of the valid ones.
+	batch := make([]event.Event, 0, len(events))
+	indexes := make([]int, 0, len(events))
+	for i, e := range events {
+		for _, fn := range c.eventDefaulterFns {
+			e = fn(ctx, e)
+		}
+		if err := e.Validate(); err != nil {
+			results[i] = err
+			continue
+		}
+		batch = append(batch, e)
+		indexes = append(indexes, i)
+	}
+	if len(batch) == 0 {
+		return results
+	}
+
+	bs, ok := c.sender.(protocol.BatchSender)
+	if !ok {
+		for j, e := range batch {
+			results[indexes[j]] = c.send(ctx, e)
+		}
+		return results
+	}
+
+	// Events have been defaulted and validated, record we are going to perform send.
+	cbs := make([]func(error), len(batch))
+	for j, e := range batch {
+		_, cbs[j] = c.observabilityService.RecordSendingEvent(ctx, e)
+	}
+	err := bs.SendBatch(ctx, batch)
+	for j := range batch {
+		result := protocol.BatchEventResult(err, j)
+		cbs[j](result)
+		results[indexes[j]] = result
+	}
+	return results
+}
+
 func (c *ceClient) Request(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
 	var resp *event.Event
 	var err error
@@ -212,6 +317,11 @@ func (c *ceClient) StartReceiver(ctx context.Context, fn interface{}) error {
 		c.inboundContextDecorators,
 		c.eventDefaulterFns,
 		c.ackMalformedEvent,
+		c.deadLetter,
+		c.retryParams,
+		c.idempotency,
+		c.middlewares,
+		c.handlerTimeout,
 	)
 	if err != nil {
 		return err
@@ -232,6 +342,20 @@ func (c *ceClient) StartReceiver(ctx context.Context, fn interface{}) error {
 		c.invoker = nil
 	}()
 
+	// When draining, the messages are handled and the protocol is opened with a context which is
+	// canceled only once the in-flight messages have been handled, or the drain timeout expired.
+	handleCtx, cancelHandle := ctx, cancel
+	if c.drainTimeout > 0 {
+		handleCtx, cancelHandle = context.WithCancel(context.WithoutCancel(ctx))
+		defer cancelHandle()
+	}
+
+	// inFlight holds a token for each message being handled, when WithMaxInFlight is configured.
+	var inFlight chan struct{}
+	if c.maxInFlight > 0 {
+		inFlight = make(chan struct{}, c.maxInFlight)
+	}
+
 	// Start Polling.
 	wg := sync.WaitGroup{}
 	for i := 0; i < c.pollGoroutines; i++ {
@@ -243,6 +367,19 @@ func (c *ceClient) StartReceiver(ctx context.Context, fn interface{}) error {
 				var respFn protocol.ResponseFn
 				var err error
 
+				if inFlight != nil {
+					select {
+					case inFlight <- struct{}{}:
+					case <-ctx.Done():
+						return
+					}
+				}
+				release := func() {
+					if inFlight != nil {
+						<-inFlight
+					}
+				}
+
 				if c.responder != nil {
 					msg, respFn, err = c.responder.Respond(ctx)
 				} else if c.receiver != nil {
@@ -251,16 +388,22 @@ func (c *ceClient) StartReceiver(ctx context.Context, fn interface{}) error {
 				}
 
 				if err == io.EOF { // Normal close
+					release()
 					return
 				}
 
 				if err != nil {
+					release()
+					if c.drainTimeout > 0 && ctx.Err() != nil { // Draining
+						return
+					}
 					cecontext.LoggerFrom(ctx).Warn("Error while receiving a message: ", err)
 					continue
 				}
 
 				callback := func() {
-					if err := c.invoker.Invoke(ctx, msg, respFn); err != nil {
+					defer release()
+					if err := invoker.Invoke(handleCtx, msg, respFn); err != nil {
 						cecontext.LoggerFrom(ctx).Warn("Error while handling a message: ", err)
 					}
 				}
@@ -279,15 +422,45 @@ func (c *ceClient) StartReceiver(ctx context.Context, fn interface{}) error {
 		}()
 	}
 
+	// drained is closed once the polling goroutines and the handlers returned, and drainExpired
+	// once the drain timeout expired: the handlers which are still running are then abandoned.
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+	drainExpired := make(chan struct{})
+	if c.drainTimeout > 0 {
+		go func() {
+			<-ctx.Done()
+			// Stop accepting the messages which would not be received anymore
+			if s, ok := c.opener.(protocol.InboundStopper); ok {
+				if err := s.StopInbound(handleCtx); err != nil {
+					cecontext.LoggerFrom(ctx).Warn("Error while stopping the inbound connection: ", err)
+				}
+			}
+			select {
+			case <-drained:
+			case <-time.After(c.drainTimeout):
+				cecontext.LoggerFrom(ctx).Warnf("Timed out after %s while draining the in-flight messages", c.drainTimeout)
+				close(drainExpired)
+			}
+			cancelHandle()
+		}()
+	}
+
 	// Start the opener, if set.
 	if c.opener != nil {
-		if err = c.opener.OpenInbound(ctx); err != nil {
+		if err = c.opener.OpenInbound(handleCtx); err != nil {
 			err = fmt.Errorf("error while opening the inbound connection: %w", err)
 			cancel()
 		}
 	}
 
-	wg.Wait()
+	select {
+	case <-drained:
+	case <-drainExpired:
+	}
 
 	return err
 }
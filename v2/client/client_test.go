@@ -16,18 +16,22 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/stretchr/testify/require"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/test"
 	"github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
 	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
 	"github.com/cloudevents/sdk-go/v2/types"
 )
@@ -401,6 +405,170 @@ func TestClientContext(t *testing.T) {
 	wg.Wait()
 }
 
+func TestClientReceiveBatch(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	c, err := client.NewHTTP(cehttp.WithListener(listener), cehttp.WithBatchAckMode(cehttp.BatchAckPartial))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	var received []string
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, e.ID())
+			if e.ID() == "1" {
+				return protocol.ResultNACK
+			}
+			return nil
+		})
+	}()
+
+	events := make([]event.Event, 3)
+	for i := range events {
+		events[i] = event.New()
+		events[i].SetID(strconv.Itoa(i))
+		events[i].SetType("unit.test.client")
+		events[i].SetSource("/unit/test/client")
+	}
+	req, err := cehttp.NewHTTPRequestFromEvents(ctx, "http://"+listener.Addr().String(), events)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// Each event invokes the receiver fn, and the response reports the result of each one
+	require.Equal(t, http.StatusMultiStatus, resp.StatusCode)
+	var items []cehttp.BatchItemResult
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&items))
+	require.Len(t, items, 3)
+	require.Equal(t, http.StatusOK, items[0].Status)
+	require.Equal(t, http.StatusInternalServerError, items[1].Status)
+	require.Equal(t, http.StatusOK, items[2].Status)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.ElementsMatch(t, []string{"0", "1", "2"}, received)
+}
+
+func TestClientRouter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	router, err := cehttp.NewRouter(cehttp.WithListener(listener))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Each route has its own receiver fn, the Router serves them all
+	received := make(chan string, 2)
+	for _, pattern := range []string{"/tenants/{id}/events", "/audit/{id}/events"} {
+		route, err := router.Route(pattern)
+		require.NoError(t, err)
+		c, err := client.New(route)
+		require.NoError(t, err)
+		go func() {
+			_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) {
+				received <- pattern + " " + cehttp.PathParamsFromContext(ctx)["id"]
+			})
+		}()
+	}
+	go func() {
+		_ = router.OpenInbound(ctx)
+	}()
+
+	for _, path := range []string{"/tenants/a/events", "/audit/b/events"} {
+		e := event.New()
+		e.SetID("1")
+		e.SetType("unit.test.client")
+		e.SetSource("/unit/test/client")
+		req, err := cehttp.NewHTTPRequestFromEvent(ctx, "http://"+listener.Addr().String()+path, e)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	require.ElementsMatch(t, []string{"/tenants/{id}/events a", "/audit/{id}/events b"}, []string{<-received, <-received})
+}
+
+func TestClientReceiveStream(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	p, err := cehttp.New(cehttp.WithListener(listener), cehttp.WithMaxBodySize(1<<20))
+	require.NoError(t, err)
+	c, err := client.New(p)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type received struct {
+		data []byte
+		size int64
+	}
+	ch := make(chan received, 1)
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event, data io.Reader) error {
+			// The data is read in chunks, not in memory
+			size, err := io.Copy(io.Discard, data)
+			ch <- received{data: e.Data(), size: size}
+			return err
+		})
+	}()
+
+	send := func(size int) int {
+		e := event.New()
+		e.SetID("1")
+		e.SetType("unit.test.client")
+		e.SetSource("/unit/test/client")
+		req, err := http.NewRequest(http.MethodPost, "http://"+listener.Addr().String(), nil)
+		require.NoError(t, err)
+		require.NoError(t, cehttp.WriteRequest(ctx, binding.ToMessage(&e), req))
+		// The data is sent chunked, so the size is limited while it's read
+		req.Body = io.NopCloser(io.LimitReader(zeroReader{}, int64(size)))
+		req.ContentLength = -1
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusOK, send(1<<20))
+	got := <-ch
+	require.Nil(t, got.data)
+	require.Equal(t, int64(1<<20), got.size)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, send(1<<20+1))
+	require.Equal(t, int64(1<<20), (<-ch).size)
+}
+
+func TestClientReceiveStreamRejectsRetriesAndDeadLetter(t *testing.T) {
+	fn := func(ctx context.Context, e event.Event, data io.Reader) error { return nil }
+	for name, opt := range map[string]client.Option{
+		"retries":     client.WithReceiverRetries(&cecontext.RetryParams{Strategy: cecontext.BackoffStrategyConstant, MaxTries: 3}),
+		"dead letter": client.WithDeadLetterSender(gochan.New(), client.DefaultDeadLetterPolicy),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c, err := client.New(gochan.New(), opt)
+			require.NoError(t, err)
+			require.ErrorContains(t, c.StartReceiver(context.Background(), fn), "io.Reader")
+		})
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
 func TestClientStartReceiverWithAckMalformedEvent(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -575,3 +743,25 @@ func (m *mockReceiver) Receive(ctx context.Context) (binding.Message, error) {
 		close(m.finished)
 	}), nil
 }
+
+// testObservabilityService is a no-op ObservabilityService to be embedded by the tests
+// recording the optional observability interfaces.
+type testObservabilityService struct{}
+
+func (testObservabilityService) InboundContextDecorators() []func(context.Context, binding.Message) context.Context {
+	return nil
+}
+
+func (testObservabilityService) RecordReceivedMalformedEvent(context.Context, error) {}
+
+func (testObservabilityService) RecordCallingInvoker(ctx context.Context, _ *event.Event) (context.Context, func(error)) {
+	return ctx, func(error) {}
+}
+
+func (testObservabilityService) RecordSendingEvent(ctx context.Context, _ event.Event) (context.Context, func(error)) {
+	return ctx, func(error) {}
+}
+
+func (testObservabilityService) RecordRequestEvent(ctx context.Context, _ event.Event) (context.Context, func(error, *event.Event)) {
+	return ctx, func(error, *event.Event) {}
+}
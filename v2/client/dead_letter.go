@@ -0,0 +1,117 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/buffering"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/binding/transformer"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	// DeadLetterReasonExtension is the extension added to dead lettered messages recording the failure reason.
+	DeadLetterReasonExtension = "deadletterreason"
+	// DeadLetterAttemptsExtension is the extension added to dead lettered messages recording how many times
+	// the processing of the message was attempted.
+	DeadLetterAttemptsExtension = "deadletterattempts"
+	// DeadLetterSourceExtension is the extension added to dead lettered messages recording the source of
+	// the original event, when known.
+	DeadLetterSourceExtension = "deadlettersource"
+)
+
+// DeadLetterPolicy configures which failures cause a received message to be forwarded
+// to the dead letter sender configured with WithDeadLetterSender.
+type DeadLetterPolicy struct {
+	// OnNACK forwards the messages for which the receiver fn returned a non ACK result.
+	OnNACK bool
+	// OnMalformed forwards the messages which cannot be converted to a valid event.
+	OnMalformed bool
+}
+
+// DefaultDeadLetterPolicy forwards both the messages nacked by the receiver fn and the malformed ones.
+var DefaultDeadLetterPolicy = DeadLetterPolicy{OnNACK: true, OnMalformed: true}
+
+type deadLetter struct {
+	sender protocol.Sender
+	policy DeadLetterPolicy
+}
+
+// prepare returns a copy of m that can be read many times, so that m can still be forwarded
+// after it has been converted to an event.
+// If the copy cannot be done, m is returned as is and forwarding is disabled for it.
+func (d *deadLetter) prepare(ctx context.Context, m binding.Message) (binding.Message, bool) {
+	if d == nil {
+		return m, false
+	}
+	c, err := buffering.CopyMessage(ctx, m)
+	if err != nil {
+		cecontext.LoggerFrom(ctx).Warnw("failed to buffer message for dead lettering", zap.Error(err))
+		return m, false
+	}
+	return c, true
+}
+
+// forward sends m to the dead letter sender when the policy allows it.
+// It returns the result that must be used to finish the original message: an ACK if m has been
+// dead lettered, the provided result otherwise.
+func (d *deadLetter) forward(ctx context.Context, m binding.Message, e *event.Event, result protocol.Result, malformed bool, attempts int) protocol.Result {
+	if malformed && !d.policy.OnMalformed || !malformed && !d.policy.OnNACK {
+		return result
+	}
+
+	reason := "unknown"
+	if result != nil {
+		reason = result.Error()
+	}
+	transformers := binding.Transformers{
+		setExtension(DeadLetterReasonExtension, reason),
+		setExtension(DeadLetterAttemptsExtension, int32(attempts)),
+	}
+	if source := originalSource(m, e); source != "" {
+		transformers = append(transformers, setExtension(DeadLetterSourceExtension, source))
+	}
+
+	// The transformers are applied while copying, because not every sender honours them.
+	dlm, err := buffering.CopyMessage(ctx, m, transformers...)
+	if err != nil {
+		cecontext.LoggerFrom(ctx).Warnw("failed to prepare message for dead lettering", zap.Error(err))
+		return result
+	}
+	if err := d.sender.Send(ctx, dlm); !protocol.IsACK(err) {
+		cecontext.LoggerFrom(ctx).Warnw("failed to forward message to dead letter sender", zap.Error(err))
+		return result
+	}
+	return protocol.NewReceipt(true, "message forwarded to dead letter sender: %s", reason)
+}
+
+func setExtension(name string, value interface{}) binding.TransformerFunc {
+	return transformer.SetExtension(name, func(interface{}) (interface{}, error) {
+		return value, nil
+	})
+}
+
+func originalSource(m binding.Message, e *event.Event) string {
+	if e != nil {
+		return e.Source()
+	}
+	if mr, ok := m.(binding.MessageMetadataReader); ok {
+		if _, v := mr.GetAttribute(spec.Source); v != nil {
+			if s, err := types.Format(v); err == nil {
+				return s
+			}
+		}
+	}
+	return ""
+}
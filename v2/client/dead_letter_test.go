@@ -0,0 +1,137 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+)
+
+func TestClientStartReceiverWithDeadLetterSender(t *testing.T) {
+	valid := event.New()
+	valid.SetID("1")
+	valid.SetType("unit.test.client")
+	valid.SetSource("/unit/test/client")
+
+	invalid := event.New()
+	invalid.SetType("unit.test.client")
+	invalid.SetSource("/unit/test/client")
+
+	testCases := []struct {
+		name          string
+		message       binding.Message
+		policy        client.DeadLetterPolicy
+		fnResult      protocol.Result
+		wantForwarded bool
+		wantAck       bool
+	}{
+		{
+			name:          "nack is forwarded",
+			message:       test.MustCreateMockBinaryMessage(valid),
+			policy:        client.DefaultDeadLetterPolicy,
+			fnResult:      protocol.NewReceipt(false, "boom"),
+			wantForwarded: true,
+			wantAck:       true,
+		},
+		{
+			name:          "error is forwarded",
+			message:       test.MustCreateMockStructuredMessage(t, valid),
+			policy:        client.DefaultDeadLetterPolicy,
+			fnResult:      errors.New("boom"),
+			wantForwarded: true,
+			wantAck:       true,
+		},
+		{
+			name:     "ack is not forwarded",
+			message:  test.MustCreateMockBinaryMessage(valid),
+			policy:   client.DefaultDeadLetterPolicy,
+			fnResult: protocol.ResultACK,
+			wantAck:  true,
+		},
+		{
+			name:     "nack is not forwarded without OnNACK",
+			message:  test.MustCreateMockBinaryMessage(valid),
+			policy:   client.DeadLetterPolicy{OnMalformed: true},
+			fnResult: protocol.NewReceipt(false, "boom"),
+		},
+		{
+			name:          "malformed is forwarded",
+			message:       binding.ToMessage(&invalid),
+			policy:        client.DefaultDeadLetterPolicy,
+			wantForwarded: true,
+			wantAck:       true,
+		},
+		{
+			name:    "malformed is not forwarded without OnMalformed",
+			message: binding.ToMessage(&invalid),
+			policy:  client.DeadLetterPolicy{OnNACK: true},
+		},
+		{
+			name:    "unknown message cannot be forwarded",
+			message: test.UnknownMessage,
+			policy:  client.DefaultDeadLetterPolicy,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			finished := make(chan error, 1)
+			in := make(chan binding.Message, 1)
+			in <- binding.WithFinish(tc.message, func(err error) { finished <- err })
+
+			dlq := make(chan binding.Message, 1)
+			c, err := client.New(gochan.Receiver(in),
+				client.WithPollGoroutines(1),
+				client.WithDeadLetterSender(gochan.Sender(dlq), tc.policy),
+			)
+			require.NoError(t, err)
+
+			go func() {
+				_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+					return tc.fnResult
+				})
+			}()
+
+			select {
+			case result := <-finished:
+				require.Equal(t, tc.wantAck, protocol.IsACK(result), "unexpected result %v", result)
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for the message to be finished")
+			}
+
+			if !tc.wantForwarded {
+				require.Len(t, dlq, 0)
+				return
+			}
+			require.Len(t, dlq, 1)
+			got, err := binding.ToEvent(ctx, <-dlq)
+			require.NoError(t, err)
+			require.NotEmpty(t, got.Extensions()[client.DeadLetterReasonExtension])
+			require.Equal(t, int32(1), got.Extensions()[client.DeadLetterAttemptsExtension])
+			require.Equal(t, "/unit/test/client", got.Extensions()[client.DeadLetterSourceExtension])
+			require.Equal(t, "unit.test.client", got.Type())
+		})
+	}
+}
+
+func TestWithDeadLetterSenderNil(t *testing.T) {
+	_, err := client.New(gochan.New(), client.WithDeadLetterSender(nil, client.DefaultDeadLetterPolicy))
+	require.Error(t, err)
+}
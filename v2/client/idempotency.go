@@ -0,0 +1,248 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// ErrIdempotencyKeyInFlight is returned by IdempotencyStore.Reserve when the key is reserved
+// by an event still being processed.
+var ErrIdempotencyKeyInFlight = errors.New("idempotency key is being processed")
+
+// IdempotencyStore records the events already processed by the receiver fn, identified by the
+// key returned by IdempotencyKey.
+// Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	// Reserve atomically marks the key as being processed.
+	// It returns false if the key is already committed, and ErrIdempotencyKeyInFlight
+	// if the key is reserved but not committed yet.
+	Reserve(ctx context.Context, key string) (bool, error)
+	// Commit marks a reserved key as processed.
+	Commit(ctx context.Context, key string) error
+	// Release forgets the reservation of the key, so the event can be processed again.
+	Release(ctx context.Context, key string) error
+}
+
+// IdempotencyKey returns the key identifying the event in an IdempotencyStore,
+// built from the source and id attributes.
+func IdempotencyKey(e *event.Event) string {
+	source := e.Source()
+	return fmt.Sprintf("%d:%s%s", len(source), source, e.ID())
+}
+
+type memoryEntry struct {
+	key       string
+	committed bool
+	expiresAt time.Time
+}
+
+type memoryIdempotencyStore struct {
+	mu         sync.Mutex
+	maxEntries int
+	ttl        time.Duration
+	// lru holds the *memoryEntry, most recently used first.
+	lru  *list.List
+	keys map[string]*list.Element
+}
+
+// NewMemoryIdempotencyStore returns an IdempotencyStore keeping all the keys in memory.
+// The store is never purged, so it's suitable only for a bounded number of events.
+func NewMemoryIdempotencyStore() IdempotencyStore {
+	return NewLRUIdempotencyStore(0, 0)
+}
+
+// NewLRUIdempotencyStore returns an IdempotencyStore keeping the keys in memory.
+// When maxEntries is greater than zero, the least recently used committed keys are evicted once
+// the store holds more than maxEntries keys. The keys reserved by events still being processed are
+// never evicted, so the store may temporarily hold more keys. When ttl is greater than zero, keys are
+// forgotten ttl after they have been last reserved, committed or checked by Reserve.
+func NewLRUIdempotencyStore(maxEntries int, ttl time.Duration) IdempotencyStore {
+	return &memoryIdempotencyStore{
+		maxEntries: maxEntries,
+		ttl:        ttl,
+		lru:        list.New(),
+		keys:       make(map[string]*list.Element),
+	}
+}
+
+func (s *memoryIdempotencyStore) Reserve(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge()
+	if el, ok := s.keys[key]; ok {
+		entry := el.Value.(*memoryEntry)
+		if !s.expired(entry) {
+			// Keep the list sorted by expiration, so purge can stop at the first unexpired key
+			entry.expiresAt = s.expiresAt()
+			s.lru.MoveToFront(el)
+			if !entry.committed {
+				return false, ErrIdempotencyKeyInFlight
+			}
+			return false, nil
+		}
+		s.remove(el)
+	}
+	s.keys[key] = s.lru.PushFront(&memoryEntry{key: key, expiresAt: s.expiresAt()})
+	s.evict()
+	return true, nil
+}
+
+func (s *memoryIdempotencyStore) Commit(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purge()
+	el, ok := s.keys[key]
+	if !ok {
+		el = s.lru.PushFront(&memoryEntry{key: key})
+		s.keys[key] = el
+	}
+	entry := el.Value.(*memoryEntry)
+	entry.committed = true
+	entry.expiresAt = s.expiresAt()
+	s.lru.MoveToFront(el)
+	s.evict()
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if el, ok := s.keys[key]; ok && !el.Value.(*memoryEntry).committed {
+		s.remove(el)
+	}
+	return nil
+}
+
+func (s *memoryIdempotencyStore) expiresAt() time.Time {
+	if s.ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(s.ttl)
+}
+
+func (s *memoryIdempotencyStore) expired(entry *memoryEntry) bool {
+	return !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt)
+}
+
+// purge removes the expired keys, starting from the least recently used one.
+func (s *memoryIdempotencyStore) purge() {
+	for el := s.lru.Back(); el != nil && s.expired(el.Value.(*memoryEntry)); el = s.lru.Back() {
+		s.remove(el)
+	}
+}
+
+// evict removes the least recently used committed keys while the store holds more than maxEntries keys.
+func (s *memoryIdempotencyStore) evict() {
+	if s.maxEntries <= 0 {
+		return
+	}
+	for el := s.lru.Back(); el != nil && s.lru.Len() > s.maxEntries; {
+		prev := el.Prev()
+		if el.Value.(*memoryEntry).committed {
+			s.remove(el)
+		}
+		el = prev
+	}
+}
+
+func (s *memoryIdempotencyStore) remove(el *list.Element) {
+	s.lru.Remove(el)
+	delete(s.keys, el.Value.(*memoryEntry).key)
+}
+
+type idempotency struct {
+	store                IdempotencyStore
+	observabilityService ObservabilityService
+	// exactlyOnce settles the binding.ExactlyOnceMessage before committing the key.
+	exactlyOnce bool
+}
+
+// reserve returns the key reserved for e, or a result if the receiver fn must not be invoked.
+func (i *idempotency) reserve(ctx context.Context, m binding.Message, e *event.Event) (string, protocol.Result) {
+	key := IdempotencyKey(e)
+	reserved, err := i.store.Reserve(ctx, key)
+	if errors.Is(err, ErrIdempotencyKeyInFlight) {
+		// The first delivery may still fail, so the duplicate must be redelivered later
+		recordIdempotencyCheck(i.observabilityService, ctx, e, true)
+		return "", protocol.NewReceipt(false, "event %s from %s is already being processed: %w", e.ID(), e.Source(), err)
+	}
+	if err != nil {
+		return "", protocol.NewReceipt(false, "failed to reserve event in the idempotency store: %w", err)
+	}
+	recordIdempotencyCheck(i.observabilityService, ctx, e, !reserved)
+	if !reserved {
+		result := protocol.NewReceipt(true, "event %s from %s has already been processed", e.ID(), e.Source())
+		if i.exactlyOnce {
+			if err := settle(ctx, m); err != nil {
+				return "", protocol.NewReceipt(false, "failed to settle duplicate message: %w", err)
+			}
+		}
+		return "", result
+	}
+	return key, nil
+}
+
+// complete commits the key if the processing succeeded, otherwise it releases it.
+func (i *idempotency) complete(ctx context.Context, m binding.Message, key string, result protocol.Result) protocol.Result {
+	if protocol.IsACK(result) && i.exactlyOnce {
+		if err := settle(ctx, m); err != nil {
+			result = protocol.NewReceipt(false, "failed to settle message: %w", err)
+		}
+	}
+	if protocol.IsACK(result) {
+		if err := i.store.Commit(ctx, key); err != nil {
+			cecontext.LoggerFrom(ctx).Warnw("failed to commit event in the idempotency store", zap.Error(err))
+		}
+		return result
+	}
+	if err := i.store.Release(ctx, key); err != nil {
+		cecontext.LoggerFrom(ctx).Warnw("failed to release event in the idempotency store", zap.Error(err))
+	}
+	return result
+}
+
+// settle invokes binding.ExactlyOnceMessage.Received on m, or the message it wraps,
+// and waits for the protocol to settle it. Other messages are considered settled.
+func settle(ctx context.Context, m binding.Message) error {
+	var eom binding.ExactlyOnceMessage
+	for m != nil && eom == nil {
+		switch mt := m.(type) {
+		case binding.ExactlyOnceMessage:
+			eom = mt
+		case binding.MessageWrapper:
+			m = mt.GetWrappedMessage()
+		default:
+			m = nil
+		}
+	}
+	if eom == nil {
+		return nil
+	}
+
+	settled := make(chan error, 1)
+	eom.Received(func(err error) {
+		settled <- err
+	})
+	select {
+	case err := <-settled:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
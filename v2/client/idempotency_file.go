@@ -0,0 +1,102 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// FileIdempotencyStore is an IdempotencyStore persisting the committed keys in a file,
+// so that duplicates are detected across restarts.
+// Reservations are kept in memory only. The file grows with every committed key and it's never compacted.
+type FileIdempotencyStore struct {
+	mu        sync.Mutex
+	file      *os.File
+	committed map[string]struct{}
+	reserved  map[string]struct{}
+}
+
+var _ IdempotencyStore = (*FileIdempotencyStore)(nil)
+
+// NewFileIdempotencyStore opens, or creates, the file at path and loads the keys already committed in it.
+// The returned store must be closed with Close.
+func NewFileIdempotencyStore(path string) (*FileIdempotencyStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	s := &FileIdempotencyStore{
+		file:      file,
+		committed: make(map[string]struct{}),
+		reserved:  make(map[string]struct{}),
+	}
+
+	// Each line holds a committed key, quoted as a Go string literal.
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		key, err := strconv.Unquote(scanner.Text())
+		if err != nil {
+			_ = file.Close()
+			return nil, fmt.Errorf("invalid idempotency store %s at line %d: %w", path, line, err)
+		}
+		s.committed[key] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *FileIdempotencyStore) Reserve(_ context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.committed[key]; ok {
+		return false, nil
+	}
+	if _, ok := s.reserved[key]; ok {
+		return false, ErrIdempotencyKeyInFlight
+	}
+	s.reserved[key] = struct{}{}
+	return true, nil
+}
+
+func (s *FileIdempotencyStore) Commit(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.reserved, key)
+	if _, ok := s.committed[key]; ok {
+		return nil
+	}
+	if _, err := s.file.WriteString(strconv.Quote(key) + "\n"); err != nil {
+		return err
+	}
+	if err := s.file.Sync(); err != nil {
+		return err
+	}
+	s.committed[key] = struct{}{}
+	return nil
+}
+
+func (s *FileIdempotencyStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.reserved, key)
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileIdempotencyStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,37 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUIdempotencyStorePurgesExpiredKeys(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRUIdempotencyStore(0, 10*time.Millisecond).(*memoryIdempotencyStore)
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprint(i)
+		ok, err := s.Reserve(ctx, key)
+		require.NoError(t, err)
+		require.True(t, ok)
+		if i%2 == 0 {
+			require.NoError(t, s.Commit(ctx, key))
+		}
+	}
+	require.Len(t, s.keys, 100)
+
+	time.Sleep(20 * time.Millisecond)
+	ok, err := s.Reserve(ctx, "new")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Len(t, s.keys, 1)
+	require.Equal(t, 1, s.lru.Len())
+}
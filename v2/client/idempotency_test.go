@@ -0,0 +1,375 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+)
+
+type exactlyOnceMessage struct {
+	*binding.EventMessage
+	settleErr error
+	received  int32
+}
+
+func (m *exactlyOnceMessage) GetWrappedMessage() binding.Message {
+	return m.EventMessage
+}
+
+func (m *exactlyOnceMessage) Received(settle func(error)) {
+	atomic.AddInt32(&m.received, 1)
+	go settle(m.settleErr)
+}
+
+func TestClientStartReceiverWithExactlyOnce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := event.New()
+	e.SetID("1")
+	e.SetType("unit.test.client")
+	e.SetSource("/unit/test/client")
+
+	messages := []*exactlyOnceMessage{
+		{EventMessage: (*binding.EventMessage)(&e), settleErr: errors.New("txn aborted")},
+		{EventMessage: (*binding.EventMessage)(&e)},
+		{EventMessage: (*binding.EventMessage)(&e)},
+	}
+	wantAck := []bool{false, true, true}
+
+	finished := make(chan error)
+	in := make(chan binding.Message, len(messages))
+	for _, m := range messages {
+		in <- binding.WithFinish(m, func(err error) { finished <- err })
+	}
+
+	c, err := client.New(gochan.Receiver(in),
+		client.WithPollGoroutines(1),
+		client.WithBlockingCallback(),
+		client.WithExactlyOnce(client.NewMemoryIdempotencyStore()),
+	)
+	require.NoError(t, err)
+
+	var invocations int32
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			atomic.AddInt32(&invocations, 1)
+			return nil
+		})
+	}()
+
+	for i := range messages {
+		select {
+		case result := <-finished:
+			require.Equal(t, wantAck[i], protocol.IsACK(result), "message %d: unexpected result %v", i, result)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the message to be finished")
+		}
+		require.Equal(t, int32(1), atomic.LoadInt32(&messages[i].received), "message %d not settled", i)
+	}
+
+	// The first settlement failed, so the event is processed again by the second delivery,
+	// while the third one is a duplicate.
+	require.Equal(t, int32(2), atomic.LoadInt32(&invocations))
+}
+
+func TestClientStartReceiverWithIdempotentReceiverConcurrentDuplicate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := event.New()
+	e.SetID("1")
+	e.SetType("unit.test.client")
+	e.SetSource("/unit/test/client")
+
+	finished := make(chan error)
+	in := make(chan binding.Message, 3)
+	deliver := func() {
+		in <- binding.WithFinish(binding.ToMessage(&e), func(err error) { finished <- err })
+	}
+
+	c, err := client.New(gochan.Receiver(in),
+		client.WithPollGoroutines(2),
+		client.WithIdempotentReceiver(client.NewMemoryIdempotencyStore()),
+	)
+	require.NoError(t, err)
+
+	var invocations int32
+	proceed := make(chan protocol.Result)
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			atomic.AddInt32(&invocations, 1)
+			return <-proceed
+		})
+	}()
+
+	waitFinished := func() error {
+		select {
+		case result := <-finished:
+			return result
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the message to be finished")
+			return nil
+		}
+	}
+
+	// The duplicate received while the first delivery is processed is not acknowledged
+	deliver()
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&invocations) == 1 }, time.Second, time.Millisecond)
+	deliver()
+	result := waitFinished()
+	require.True(t, protocol.IsNACK(result), "unexpected result %v", result)
+
+	// The first delivery fails, so the redelivered event is processed again
+	proceed <- protocol.NewReceipt(false, "processing failed")
+	result = waitFinished()
+	require.True(t, protocol.IsNACK(result), "unexpected result %v", result)
+
+	deliver()
+	proceed <- nil
+	result = waitFinished()
+	require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+	require.Equal(t, int32(2), atomic.LoadInt32(&invocations))
+}
+
+func TestIdempotencyOptionsConflict(t *testing.T) {
+	store := client.NewMemoryIdempotencyStore()
+	_, err := client.New(gochan.New(),
+		client.WithIdempotentReceiver(store),
+		client.WithExactlyOnce(store),
+	)
+	require.Error(t, err)
+
+	_, err = client.New(gochan.New(),
+		client.WithExactlyOnce(store),
+		client.WithIdempotentReceiver(store),
+	)
+	require.Error(t, err)
+}
+
+func TestMemoryIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	s := client.NewMemoryIdempotencyStore()
+
+	ok, err := s.Reserve(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = s.Reserve(ctx, "a")
+	require.ErrorIs(t, err, client.ErrIdempotencyKeyInFlight)
+	require.False(t, ok)
+
+	require.NoError(t, s.Release(ctx, "a"))
+	ok, err = s.Reserve(ctx, "a")
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, s.Commit(ctx, "a"))
+	require.NoError(t, s.Release(ctx, "a"))
+	ok, err = s.Reserve(ctx, "a")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	a := event.New()
+	a.SetSource("a#b")
+	a.SetID("c")
+	b := event.New()
+	b.SetSource("a")
+	b.SetID("b#c")
+	require.NotEqual(t, client.IdempotencyKey(&a), client.IdempotencyKey(&b))
+}
+
+type idempotencyObservabilityService struct {
+	testObservabilityService
+
+	hits, misses int32
+}
+
+func (o *idempotencyObservabilityService) RecordIdempotencyCheck(_ context.Context, _ *event.Event, duplicate bool) {
+	if duplicate {
+		atomic.AddInt32(&o.hits, 1)
+	} else {
+		atomic.AddInt32(&o.misses, 1)
+	}
+}
+
+func TestClientStartReceiverWithIdempotentReceiver(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newEvent := func(id string) *event.Event {
+		e := event.New()
+		e.SetID(id)
+		e.SetType("unit.test.client")
+		e.SetSource("/unit/test/client")
+		return &e
+	}
+	events := []*event.Event{newEvent("1"), newEvent("2"), newEvent("1"), newEvent("3"), newEvent("2")}
+
+	finished := make(chan error)
+	in := make(chan binding.Message, len(events))
+	for _, e := range events {
+		in <- binding.WithFinish(binding.ToMessage(e), func(err error) { finished <- err })
+	}
+
+	obs := &idempotencyObservabilityService{}
+	c, err := client.New(gochan.Receiver(in),
+		client.WithPollGoroutines(1),
+		client.WithBlockingCallback(),
+		client.WithObservabilityService(obs),
+		client.WithIdempotentReceiver(client.NewLRUIdempotencyStore(10, time.Minute)),
+	)
+	require.NoError(t, err)
+
+	var invocations int32
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			atomic.AddInt32(&invocations, 1)
+			return nil
+		})
+	}()
+
+	for range events {
+		select {
+		case result := <-finished:
+			require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for the message to be finished")
+		}
+	}
+
+	require.Equal(t, int32(3), atomic.LoadInt32(&invocations))
+	require.Equal(t, int32(2), atomic.LoadInt32(&obs.hits))
+	require.Equal(t, int32(3), atomic.LoadInt32(&obs.misses))
+}
+
+func TestLRUIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("eviction", func(t *testing.T) {
+		s := client.NewLRUIdempotencyStore(2, 0)
+		for _, k := range []string{"a", "b", "c"} {
+			ok, err := s.Reserve(ctx, k)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.NoError(t, s.Commit(ctx, k))
+		}
+		// "a" is the least recently used, so it has been evicted
+		ok, err := s.Reserve(ctx, "a")
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = s.Reserve(ctx, "c")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+
+	t.Run("in flight keys are not evicted", func(t *testing.T) {
+		s := client.NewLRUIdempotencyStore(2, 0)
+		ok, err := s.Reserve(ctx, "a")
+		require.NoError(t, err)
+		require.True(t, ok)
+		for _, k := range []string{"b", "c"} {
+			ok, err := s.Reserve(ctx, k)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.NoError(t, s.Commit(ctx, k))
+		}
+		// "a" is still being processed, so "b" has been evicted instead
+		ok, err = s.Reserve(ctx, "a")
+		require.ErrorIs(t, err, client.ErrIdempotencyKeyInFlight)
+		require.False(t, ok)
+		ok, err = s.Reserve(ctx, "b")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		s := client.NewLRUIdempotencyStore(0, 10*time.Millisecond)
+		ok, err := s.Reserve(ctx, "a")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NoError(t, s.Commit(ctx, "a"))
+
+		ok, err = s.Reserve(ctx, "a")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		time.Sleep(20 * time.Millisecond)
+		ok, err = s.Reserve(ctx, "a")
+		require.NoError(t, err)
+		require.True(t, ok)
+	})
+
+	t.Run("ttl is refreshed by reserve", func(t *testing.T) {
+		s := client.NewLRUIdempotencyStore(0, 300*time.Millisecond)
+		for _, k := range []string{"a", "b"} {
+			ok, err := s.Reserve(ctx, k)
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.NoError(t, s.Commit(ctx, k))
+		}
+
+		// Checking "a" moves it ahead of "b", which expires first
+		time.Sleep(200 * time.Millisecond)
+		ok, err := s.Reserve(ctx, "a")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		time.Sleep(200 * time.Millisecond)
+		ok, err = s.Reserve(ctx, "b")
+		require.NoError(t, err)
+		require.True(t, ok)
+		ok, err = s.Reserve(ctx, "a")
+		require.NoError(t, err)
+		require.False(t, ok)
+	})
+}
+
+func TestFileIdempotencyStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "store")
+
+	s, err := client.NewFileIdempotencyStore(path)
+	require.NoError(t, err)
+	for _, k := range []string{"a", "multi\nline"} {
+		ok, err := s.Reserve(ctx, k)
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.NoError(t, s.Commit(ctx, k))
+	}
+	ok, err := s.Reserve(ctx, "b")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = s.Reserve(ctx, "b")
+	require.ErrorIs(t, err, client.ErrIdempotencyKeyInFlight)
+	require.False(t, ok)
+	require.NoError(t, s.Close())
+
+	// Committed keys survive a restart, reservations don't
+	s, err = client.NewFileIdempotencyStore(path)
+	require.NoError(t, err)
+	defer s.Close()
+	for k, want := range map[string]bool{"a": false, "multi\nline": false, "b": true} {
+		ok, err := s.Reserve(ctx, k)
+		require.NoError(t, err)
+		require.Equal(t, want, ok, k)
+	}
+}
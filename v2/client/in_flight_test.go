@@ -0,0 +1,319 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+	cehttp "github.com/cloudevents/sdk-go/v2/protocol/http"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func TestClientStartReceiverWithMaxInFlight(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const messages = 20
+	finished := make(chan error, messages)
+	in := make(chan binding.Message, messages)
+	for i := 0; i < messages; i++ {
+		in <- binding.WithFinish(test.MinMessage(), func(err error) { finished <- err })
+	}
+
+	c, err := client.New(gochan.Receiver(in),
+		client.WithPollGoroutines(4),
+		client.WithMaxInFlight(2),
+	)
+	require.NoError(t, err)
+
+	var current, max int32
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			n := atomic.AddInt32(&current, 1)
+			defer atomic.AddInt32(&current, -1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+	}()
+
+	for i := 0; i < messages; i++ {
+		select {
+		case err := <-finished:
+			require.True(t, protocol.IsACK(err), "unexpected finish error %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the messages to be finished")
+		}
+	}
+	require.LessOrEqual(t, atomic.LoadInt32(&max), int32(2))
+}
+
+func TestClientStartReceiverWithHandlerTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	finished := make(chan error, 1)
+	in := make(chan binding.Message, 1)
+	in <- binding.WithFinish(test.MinMessage(), func(err error) { finished <- err })
+
+	c, err := client.New(gochan.Receiver(in),
+		client.WithPollGoroutines(1),
+		client.WithHandlerTimeout(10*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+
+	select {
+	case err := <-finished:
+		require.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected finish error %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the message to be finished")
+	}
+}
+
+// openerReceiver is a gochan.Receiver recording when it's closed,
+// which happens once the context passed to OpenInbound is done.
+type openerReceiver struct {
+	gochan.Receiver
+	closed chan struct{}
+}
+
+func (o *openerReceiver) OpenInbound(ctx context.Context) error {
+	<-ctx.Done()
+	close(o.closed)
+	return nil
+}
+
+func TestClientStartReceiverWithDrainTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	finished := make(chan error, 1)
+	in := make(chan binding.Message, 1)
+	in <- binding.WithFinish(test.MinMessage(), func(err error) { finished <- err })
+	p := &openerReceiver{Receiver: in, closed: make(chan struct{})}
+
+	c, err := client.New(p,
+		client.WithPollGoroutines(1),
+		client.WithDrainTimeout(time.Second),
+	)
+	require.NoError(t, err)
+
+	handling := make(chan struct{})
+	release := make(chan struct{})
+	var handlerCtxErr error
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			close(handling)
+			<-release
+			handlerCtxErr = ctx.Err()
+			return nil
+		}))
+	}()
+
+	<-handling
+	cancel()
+
+	// The protocol must not be closed while the message is being handled
+	select {
+	case <-p.closed:
+		t.Fatal("protocol closed before the in-flight message has been handled")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+
+	select {
+	case err := <-finished:
+		require.True(t, protocol.IsACK(err), "unexpected finish error %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the message to be finished")
+	}
+	select {
+	case <-p.closed:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the protocol to be closed")
+	}
+	wg.Wait()
+	require.NoError(t, handlerCtxErr)
+}
+
+func TestClientStartReceiverDrainTimeoutExpires(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	finished := make(chan error, 1)
+	in := make(chan binding.Message, 1)
+	in <- binding.WithFinish(test.MinMessage(), func(err error) { finished <- err })
+	p := &openerReceiver{Receiver: in, closed: make(chan struct{})}
+
+	c, err := client.New(p,
+		client.WithPollGoroutines(1),
+		client.WithDrainTimeout(20*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	handling := make(chan struct{})
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			close(handling)
+			// The handler context is canceled once the drain timeout expires
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+
+	<-handling
+	cancel()
+
+	select {
+	case err := <-finished:
+		require.True(t, errors.Is(err, context.Canceled), "unexpected finish error %v", err)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the message to be finished")
+	}
+	select {
+	case <-p.closed:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the protocol to be closed")
+	}
+}
+
+func TestClientStartReceiverDrainTimeoutBoundsShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const messages = 3
+	finished := make(chan error, messages)
+	in := make(chan binding.Message, messages)
+	for i := 0; i < messages; i++ {
+		in <- binding.WithFinish(test.MinMessage(), func(err error) { finished <- err })
+	}
+	p := &openerReceiver{Receiver: in, closed: make(chan struct{})}
+
+	c, err := client.New(p,
+		client.WithPollGoroutines(2),
+		client.WithMaxInFlight(1),
+		client.WithDrainTimeout(20*time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	handling := make(chan struct{}, messages)
+	block := make(chan struct{})
+	defer close(block)
+	returned := make(chan error)
+	go func() {
+		returned <- c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			handling <- struct{}{}
+			// The handler ignores the cancellation of its context
+			<-block
+			return nil
+		})
+	}()
+
+	// One handler is running, while the other polling goroutine waits for an in-flight token
+	<-handling
+	cancel()
+
+	select {
+	case err := <-returned:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("StartReceiver didn't return after the drain timeout")
+	}
+	require.Len(t, handling, 0)
+}
+
+func TestClientStartReceiverDrainStopsHTTPServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	p, err := cehttp.New(cehttp.WithPort(0))
+	require.NoError(t, err)
+	c, err := client.New(p, client.WithDrainTimeout(time.Second))
+	require.NoError(t, err)
+
+	handling := make(chan struct{})
+	release := make(chan struct{})
+	returned := make(chan error)
+	go func() {
+		returned <- c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			close(handling)
+			<-release
+			return nil
+		})
+	}()
+	require.Eventually(t, func() bool { return p.GetListeningPort() > 0 }, time.Second, time.Millisecond)
+
+	sp, err := cehttp.New(cehttp.WithTarget(fmt.Sprintf("http://localhost:%d", p.GetListeningPort())))
+	require.NoError(t, err)
+	sender, err := client.New(sp)
+	require.NoError(t, err)
+	sent := make(chan protocol.Result)
+	go func() {
+		sent <- sender.Send(context.Background(), test.MinEvent())
+	}()
+	<-handling
+	cancel()
+
+	// The requests received while draining are refused, instead of hanging until the server is closed
+	require.Eventually(t, func() bool {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		result := sender.Send(ctx, test.MinEvent())
+		return !protocol.IsACK(result) && !errors.Is(result, context.DeadlineExceeded)
+	}, time.Second, 10*time.Millisecond)
+
+	// The in-flight request is still responded
+	close(release)
+	select {
+	case result := <-sent:
+		require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the in-flight request to be responded")
+	}
+	select {
+	case err := <-returned:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("StartReceiver didn't return after the drain")
+	}
+}
+
+func TestInFlightOptionsValidation(t *testing.T) {
+	_, err := client.New(gochan.New(), client.WithMaxInFlight(0))
+	require.Error(t, err)
+	_, err = client.New(gochan.New(), client.WithHandlerTimeout(0))
+	require.Error(t, err)
+	_, err = client.New(gochan.New(), client.WithDrainTimeout(-time.Second))
+	require.Error(t, err)
+}
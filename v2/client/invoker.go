@@ -8,6 +8,11 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
+	"runtime/debug"
+	"time"
+
+	"go.uber.org/zap"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
 	cecontext "github.com/cloudevents/sdk-go/v2/context"
@@ -29,12 +34,25 @@ func newReceiveInvoker(
 	inboundContextDecorators []func(context.Context, binding.Message) context.Context,
 	fns []EventDefaulter,
 	ackMalformedEvent bool,
+	deadLetter *deadLetter,
+	retryParams *cecontext.RetryParams,
+	idempotency *idempotency,
+	middlewares []Middleware,
+	handlerTimeout time.Duration,
 ) (Invoker, error) {
 	r := &receiveInvoker{
 		eventDefaulterFns:        fns,
 		observabilityService:     observabilityService,
 		inboundContextDecorators: inboundContextDecorators,
 		ackMalformedEvent:        ackMalformedEvent,
+		deadLetter:               deadLetter,
+		retryParams:              retryParams,
+		handlerTimeout:           handlerTimeout,
+	}
+	if idempotency != nil {
+		idem := *idempotency
+		idem.observabilityService = observabilityService
+		r.idempotency = &idem
 	}
 
 	if fn, err := receiver(fn); err != nil {
@@ -42,58 +60,139 @@ func newReceiveInvoker(
 	} else {
 		r.fn = fn
 	}
+	// A streamed data can be read only once, it can't be read again by a retry or be forwarded
+	if r.fn.hasDataIn && retryParams != nil {
+		return nil, fmt.Errorf("a receiver fn taking an io.Reader can not be used with receiver retries")
+	}
+	if r.fn.hasDataIn && deadLetter != nil {
+		return nil, fmt.Errorf("a receiver fn taking an io.Reader can not be used with a dead letter sender")
+	}
+	r.handler = chain(r.invokeReceiverFn, middlewares)
 
 	return r, nil
 }
 
 type receiveInvoker struct {
 	fn                       *receiverFn
+	handler                  Handler
 	observabilityService     ObservabilityService
 	eventDefaulterFns        []EventDefaulter
 	inboundContextDecorators []func(context.Context, binding.Message) context.Context
 	ackMalformedEvent        bool
+	deadLetter               *deadLetter
+	retryParams              *cecontext.RetryParams
+	idempotency              *idempotency
+	handlerTimeout           time.Duration
 }
 
 func (r *receiveInvoker) Invoke(ctx context.Context, m binding.Message, respFn protocol.ResponseFn) (err error) {
+	// Track whether the response fn has been invoked, so it can be invoked in case of panic.
+	responded := false
+	if respFn != nil {
+		originalRespFn := respFn
+		respFn = func(ctx context.Context, m binding.Message, r protocol.Result, transformers ...binding.Transformer) error {
+			responded = true
+			return originalRespFn(ctx, m, r, transformers...)
+		}
+	}
 	defer func() {
+		// Whatever happened, the message must be finished so that the protocol can redeliver it.
+		if rec := recover(); rec != nil {
+			err = r.recovered(ctx, nil, rec)
+			if respFn != nil && !responded {
+				err = respFn(ctx, nil, err)
+			}
+		}
 		err = m.Finish(err)
 	}()
 
 	var respMsg binding.Message
 	var result protocol.Result
 
-	e, eventErr := binding.ToEvent(ctx, m)
+	// When a dead letter sender is configured, the event is read from an in memory copy of m,
+	// so the message can still be forwarded after the receiver fn processed it.
+	// The receiver fns taking an io.Reader are rejected in that case, so their data is always streamed.
+	rm, forwardable := r.deadLetter.prepare(ctx, m)
+	if forwardable {
+		defer func() { _ = rm.Finish(nil) }()
+	}
+
+	// The data is streamed to the receiver fns taking an io.Reader, in place of being read in memory
+	var e *event.Event
+	var data io.Reader
+	var eventErr error
+	if r.fn.hasDataIn {
+		e, data, eventErr = binding.ToStreamingEvent(ctx, rm)
+	} else {
+		e, eventErr = binding.ToEvent(ctx, rm)
+	}
 	switch {
 	case eventErr != nil && r.fn.hasEventIn:
 		r.observabilityService.RecordReceivedMalformedEvent(ctx, eventErr)
-		return respFn(ctx, nil, protocol.NewReceipt(r.ackMalformedEvent, "failed to convert Message to Event: %w", eventErr))
+		result = protocol.NewReceipt(r.ackMalformedEvent, "failed to convert Message to Event: %w", eventErr)
+		if forwardable {
+			result = r.deadLetter.forward(ctx, rm, nil, result, true, 1)
+		}
+		return respFn(ctx, nil, result)
 	case r.fn != nil:
 		// Check if event is valid before invoking the receiver function
 		if e != nil {
 			if validationErr := e.Validate(); validationErr != nil {
 				r.observabilityService.RecordReceivedMalformedEvent(ctx, validationErr)
-				return respFn(ctx, nil, protocol.NewReceipt(r.ackMalformedEvent, "validation error in incoming event: %w", validationErr))
+				result = protocol.NewReceipt(r.ackMalformedEvent, "validation error in incoming event: %w", validationErr)
+				if forwardable {
+					result = r.deadLetter.forward(ctx, rm, e, result, true, 1)
+				}
+				return respFn(ctx, nil, result)
+			}
+		}
+
+		ctx = computeInboundContext(m, ctx, r.inboundContextDecorators)
+		if data != nil {
+			ctx = withData(ctx, data)
+		}
+
+		// Skip the events already processed, when an idempotency store is configured
+		var key string
+		if r.idempotency != nil && e != nil {
+			if key, result = r.idempotency.reserve(ctx, m, e); key == "" {
+				return respFn(ctx, nil, result)
 			}
 		}
 
-		// Let's invoke the receiver fn
+		// Let's invoke the receiver fn, retrying it if configured to do so
+		inboundCtx := ctx
+		start := time.Now()
+		attempts := 1
 		var resp *event.Event
-		resp, result = func() (resp *event.Event, result protocol.Result) {
-			defer func() {
-				if r := recover(); r != nil {
-					result = fmt.Errorf("call to Invoker.Invoke(...) has panicked: %v", r)
-					cecontext.LoggerFrom(ctx).Error(result)
+		ctx, resp, result = r.invokeFn(inboundCtx, e)
+		if r.retryParams != nil {
+			for isRetriable(result) {
+				if backoffErr := r.retryParams.BackoffSince(inboundCtx, start, attempts, 0); backoffErr != nil {
+					cecontext.LoggerFrom(inboundCtx).Debugw("backoff error, will not invoke the receiver fn again", zap.Error(backoffErr))
+					break
 				}
-			}()
-			ctx = computeInboundContext(m, ctx, r.inboundContextDecorators)
+				recordRetryingInvoker(r.observabilityService, inboundCtx, e, attempts, result)
+				attempts++
+				ctx, resp, result = r.invokeFn(inboundCtx, e)
+			}
+			recordInvokerRetries(r.observabilityService, inboundCtx, e, attempts, result)
+		}
 
-			var cb func(error)
-			ctx, cb = r.observabilityService.RecordCallingInvoker(ctx, e)
+		// The receiver fn can report the event as malformed, e.g. when its data cannot be decoded
+		malformed, isMalformed := isMalformedEvent(result)
+		if isMalformed {
+			r.observabilityService.RecordReceivedMalformedEvent(ctx, malformed.err)
+			result = protocol.NewReceipt(r.ackMalformedEvent, "malformed incoming event: %w", malformed.err)
+		}
 
-			resp, result = r.fn.invoke(ctx, e)
-			defer cb(result)
-			return
-		}()
+		if key != "" {
+			result = r.idempotency.complete(inboundCtx, m, key, result)
+		}
+
+		if forwardable && !protocol.IsACK(result) {
+			result = r.deadLetter.forward(ctx, rm, e, result, isMalformed, attempts)
+		}
 
 		if respFn == nil {
 			break
@@ -125,6 +224,51 @@ func (r *receiveInvoker) Invoke(ctx context.Context, m binding.Message, respFn p
 	return respFn(ctx, respMsg, result)
 }
 
+// invokeFn invokes the receiver fn once through the middlewares, recovering from any panic.
+func (r *receiveInvoker) invokeFn(inboundCtx context.Context, e *event.Event) (ctx context.Context, resp *event.Event, result protocol.Result) {
+	var cb func(error)
+	ctx, cb = r.observabilityService.RecordCallingInvoker(inboundCtx, e)
+	// Registered before the recover, so it's invoked with the result of the recovered panic.
+	defer func() { cb(result) }()
+	defer func() {
+		if rec := recover(); rec != nil {
+			resp, result = nil, r.recovered(ctx, e, rec)
+		}
+	}()
+
+	// The timeout applies only to the receiver fn, not to the processing of its outcome.
+	handlerCtx := ctx
+	if r.handlerTimeout > 0 {
+		var cancel context.CancelFunc
+		handlerCtx, cancel = context.WithTimeout(ctx, r.handlerTimeout)
+		defer cancel()
+	}
+
+	if e != nil {
+		resp, result = r.handler(handlerCtx, *e)
+	} else {
+		resp, result = r.handler(handlerCtx, event.Event{})
+	}
+	return ctx, resp, result
+}
+
+// recovered logs and records a recovered panic, returning the NACK result it must be turned into.
+func (r *receiveInvoker) recovered(ctx context.Context, e *event.Event, rec interface{}) protocol.Result {
+	stack := debug.Stack()
+	result := protocol.NewReceipt(false, "call to Invoker.Invoke(...) has panicked: %v", rec)
+	cecontext.LoggerFrom(ctx).Errorw("recovered from panic while handling a message", zap.Error(result), zap.ByteString("stack", stack))
+	recordInvokerPanic(r.observabilityService, ctx, e, rec, stack)
+	return result
+}
+
+// invokeReceiverFn is the innermost Handler, wrapped by the middlewares.
+func (r *receiveInvoker) invokeReceiverFn(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+	if !r.fn.hasEventIn {
+		return r.fn.invoke(ctx, nil)
+	}
+	return r.fn.invoke(ctx, &e)
+}
+
 func (r *receiveInvoker) IsReceiver() bool {
 	return !r.fn.hasEventOut
 }
@@ -0,0 +1,85 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+)
+
+func TestClientStartReceiverWithReceiverMiddleware(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := event.New()
+	e.SetID("1")
+	e.SetType("unit.test.client")
+	e.SetSource("/unit/test/client")
+
+	in := make(chan binding.Message, 1)
+	out := make(chan gochan.ChanResponderResponse, 1)
+	in <- binding.ToMessage(&e)
+
+	var calls []string
+	c, err := client.New(&gochan.Responder{In: in, Out: out},
+		client.WithPollGoroutines(1),
+		// The outer middleware replaces the result and the response event
+		client.WithReceiverMiddleware(func(next client.Handler) client.Handler {
+			return func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+				calls = append(calls, "outer")
+				resp, result := next(ctx, e)
+				require.True(t, protocol.IsNACK(result))
+				require.Equal(t, "unit.test.client.response", resp.Type())
+				resp.SetType("unit.test.client.replaced")
+				return resp, protocol.ResultACK
+			}
+		}),
+		// The inner middleware modifies the event passed to the receiver fn
+		client.WithReceiverMiddleware(func(next client.Handler) client.Handler {
+			return func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+				calls = append(calls, "inner")
+				e.SetExtension("middleware", "inner")
+				return next(ctx, e)
+			}
+		}),
+	)
+	require.NoError(t, err)
+
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+			calls = append(calls, "fn")
+			require.Equal(t, "inner", e.Extensions()["middleware"])
+			resp := e.Clone()
+			resp.SetType("unit.test.client.response")
+			return &resp, protocol.ResultNACK
+		})
+	}()
+
+	select {
+	case resp := <-out:
+		require.True(t, protocol.IsACK(resp.Result), "unexpected result %v", resp.Result)
+		got, err := binding.ToEvent(ctx, resp.Message)
+		require.NoError(t, err)
+		require.Equal(t, "unit.test.client.replaced", got.Type())
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the response")
+	}
+	require.Equal(t, []string{"outer", "inner", "fn"}, calls)
+}
+
+func TestWithReceiverMiddlewareNil(t *testing.T) {
+	_, err := client.New(gochan.New(), client.WithReceiverMiddleware(nil))
+	require.Error(t, err)
+}
@@ -52,3 +52,54 @@ func (n noopObservabilityService) RecordSendingEvent(ctx context.Context, event
 func (n noopObservabilityService) RecordRequestEvent(ctx context.Context, e event.Event) (context.Context, func(errOrResult error, event *event.Event)) {
 	return ctx, func(errOrResult error, event *event.Event) {}
 }
+
+// RetryObservabilityService is an optional interface an ObservabilityService can implement
+// to record the retries of the receiver fn configured with WithReceiverRetries.
+type RetryObservabilityService interface {
+	// RecordRetryingInvoker is invoked before the user function is invoked again,
+	// with the number of attempts done so far and the result of the last one.
+	RecordRetryingInvoker(ctx context.Context, event *event.Event, attempts int, errOrResult error)
+	// RecordInvokerRetries is invoked once the user function won't be invoked anymore,
+	// with the total number of attempts and the final result.
+	RecordInvokerRetries(ctx context.Context, event *event.Event, attempts int, errOrResult error)
+}
+
+func recordRetryingInvoker(o ObservabilityService, ctx context.Context, event *event.Event, attempts int, errOrResult error) {
+	if ro, ok := o.(RetryObservabilityService); ok {
+		ro.RecordRetryingInvoker(ctx, event, attempts, errOrResult)
+	}
+}
+
+func recordInvokerRetries(o ObservabilityService, ctx context.Context, event *event.Event, attempts int, errOrResult error) {
+	if ro, ok := o.(RetryObservabilityService); ok {
+		ro.RecordInvokerRetries(ctx, event, attempts, errOrResult)
+	}
+}
+
+// IdempotencyObservabilityService is an optional interface an ObservabilityService can implement
+// to record the checks against the IdempotencyStore configured with WithIdempotentReceiver or WithExactlyOnce.
+type IdempotencyObservabilityService interface {
+	// RecordIdempotencyCheck is invoked after the event has been checked against the store,
+	// duplicate is true if the event has already been processed (hit) and false otherwise (miss).
+	RecordIdempotencyCheck(ctx context.Context, event *event.Event, duplicate bool)
+}
+
+func recordIdempotencyCheck(o ObservabilityService, ctx context.Context, event *event.Event, duplicate bool) {
+	if io, ok := o.(IdempotencyObservabilityService); ok {
+		io.RecordIdempotencyCheck(ctx, event, duplicate)
+	}
+}
+
+// PanicObservabilityService is an optional interface an ObservabilityService can implement
+// to record the panics recovered while handling a message.
+type PanicObservabilityService interface {
+	// RecordInvokerPanic is invoked with the recovered value and the stack trace of the panic.
+	// The event is nil if the panic didn't happen while invoking the user function.
+	RecordInvokerPanic(ctx context.Context, event *event.Event, recovered interface{}, stack []byte)
+}
+
+func recordInvokerPanic(o ObservabilityService, ctx context.Context, event *event.Event, recovered interface{}, stack []byte) {
+	if po, ok := o.(PanicObservabilityService); ok {
+		po.RecordInvokerPanic(ctx, event, recovered, stack)
+	}
+}
@@ -8,8 +8,11 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
 // Option is the function signature required to be considered an client.Option.
@@ -139,3 +142,150 @@ func WithAckMalformedEvent() Option {
 		return nil
 	}
 }
+
+// WithDeadLetterSender configures a sender the messages received within StartReceiver are forwarded to,
+// when their processing fails according to the provided policy.
+// Forwarded messages carry the DeadLetterReasonExtension, DeadLetterAttemptsExtension and
+// DeadLetterSourceExtension extensions, and the original message is acknowledged once the
+// dead letter sender accepted it.
+// The messages are buffered in memory to be forwarded, so StartReceiver rejects the receiver fns
+// taking an io.Reader.
+func WithDeadLetterSender(sender protocol.Sender, policy DeadLetterPolicy) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if sender == nil {
+				return fmt.Errorf("client option was given a nil dead letter sender")
+			}
+			c.deadLetter = &deadLetter{sender: sender, policy: policy}
+		}
+		return nil
+	}
+}
+
+// WithReceiverRetries configures StartReceiver to invoke the receiver fn again, following the provided
+// retry params, when it returns a non ACK result. The received message is finished only once the
+// receiver fn succeeded or the retries have been exhausted.
+// Results marked with NewPermanentResult are never retried.
+// If the configured ObservabilityService implements RetryObservabilityService, retries are recorded through it.
+// A streamed data can't be read again, so StartReceiver rejects the receiver fns taking an io.Reader.
+func WithReceiverRetries(params *cecontext.RetryParams) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if params == nil {
+				return fmt.Errorf("client option was given nil retry params")
+			}
+			c.retryParams = params
+		}
+		return nil
+	}
+}
+
+// WithExactlyOnce configures StartReceiver to process each event at most once, as identified by
+// its source and id in the provided store, and to settle the received messages implementing
+// binding.ExactlyOnceMessage before acknowledging them.
+// Events already committed in the store are acknowledged without invoking the receiver fn, while
+// the duplicates of events still being processed are not acknowledged, so they can be redelivered.
+// When the protocol supports QoS 2 (e.g. the kafka_sarama protocol configured with WithExactlyOnce),
+// the message is settled through binding.ExactlyOnceMessage.Received once the receiver fn succeeded,
+// and the event is committed in the store only if the settlement succeeds.
+// It cannot be combined with WithIdempotentReceiver.
+func WithExactlyOnce(store IdempotencyStore) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if store == nil {
+				return fmt.Errorf("client option was given a nil idempotency store")
+			}
+			if c.idempotency != nil {
+				return fmt.Errorf("client option WithExactlyOnce conflicts with an idempotency store already configured")
+			}
+			c.idempotency = &idempotency{store: store, exactlyOnce: true}
+		}
+		return nil
+	}
+}
+
+// WithIdempotentReceiver configures StartReceiver to check every event, identified by its source and id,
+// against the provided store before invoking the receiver fn. Events already processed are acknowledged
+// without invoking the receiver fn, while the duplicates of events still being processed are not
+// acknowledged, so they can be redelivered. Events whose processing fails are released from the store,
+// so they can be processed again when redelivered.
+// If the configured ObservabilityService implements IdempotencyObservabilityService, checks are recorded through it.
+// It cannot be combined with WithExactlyOnce.
+func WithIdempotentReceiver(store IdempotencyStore) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if store == nil {
+				return fmt.Errorf("client option was given a nil idempotency store")
+			}
+			if c.idempotency != nil {
+				return fmt.Errorf("client option WithIdempotentReceiver conflicts with an idempotency store already configured")
+			}
+			c.idempotency = &idempotency{store: store}
+		}
+		return nil
+	}
+}
+
+// WithReceiverMiddleware appends a middleware wrapping every invocation of the receiver fn within StartReceiver.
+// The first middleware configured is the outermost one. Middlewares can inspect or replace the event passed
+// to the receiver fn, as well as the returned response event and protocol.Result, e.g. to enforce timeouts,
+// authorize events or log the processing outcome.
+func WithReceiverMiddleware(mw Middleware) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if mw == nil {
+				return fmt.Errorf("client option was given a nil middleware")
+			}
+			c.middlewares = append(c.middlewares, mw)
+		}
+		return nil
+	}
+}
+
+// WithMaxInFlight limits to n the messages handled concurrently within StartReceiver.
+// Once n messages are being handled, the poll goroutines stop receiving messages from the protocol
+// until one of the handlers completes, applying backpressure to the protocol.
+func WithMaxInFlight(n int) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if n <= 0 {
+				return fmt.Errorf("client option was given a non positive max in flight: %d", n)
+			}
+			c.maxInFlight = n
+		}
+		return nil
+	}
+}
+
+// WithHandlerTimeout sets a timeout to every invocation of the receiver fn within StartReceiver.
+// The context passed to the receiver fn is canceled once the timeout expires.
+func WithHandlerTimeout(timeout time.Duration) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if timeout <= 0 {
+				return fmt.Errorf("client option was given a non positive handler timeout: %s", timeout)
+			}
+			c.handlerTimeout = timeout
+		}
+		return nil
+	}
+}
+
+// WithDrainTimeout configures StartReceiver to drain the in-flight messages when its context is done:
+// no more messages are received, while the messages already received are handled, for at most the provided
+// timeout, before the protocol is closed. The context passed to the receiver fn is canceled only once the
+// drain completes or the timeout expires. StartReceiver returns once the timeout expired, without waiting
+// for the receiver fn invocations which ignore the cancellation of their context.
+// If the protocol implements protocol.InboundStopper, e.g. the HTTP protocol, it's stopped from accepting
+// new messages as soon as the drain starts.
+func WithDrainTimeout(timeout time.Duration) Option {
+	return func(i interface{}) error {
+		if c, ok := i.(*ceClient); ok {
+			if timeout <= 0 {
+				return fmt.Errorf("client option was given a non positive drain timeout: %s", timeout)
+			}
+			c.drainTimeout = timeout
+		}
+		return nil
+	}
+}
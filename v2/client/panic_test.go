@@ -0,0 +1,148 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+)
+
+type panicObservabilityService struct {
+	testObservabilityService
+	mu        sync.Mutex
+	recovered []interface{}
+	stacks    [][]byte
+}
+
+func (o *panicObservabilityService) RecordInvokerPanic(_ context.Context, _ *event.Event, recovered interface{}, stack []byte) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.recovered = append(o.recovered, recovered)
+	o.stacks = append(o.stacks, stack)
+}
+
+func TestClientStartReceiverRecoversPanic(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := event.New()
+	e.SetID("1")
+	e.SetType("unit.test.client")
+	e.SetSource("/unit/test/client")
+
+	// The message must be finished with a NACK, so that the protocol can redeliver it
+	finished := make(chan error, 1)
+	in := make(chan binding.Message, 1)
+	in <- binding.WithFinish(binding.ToMessage(&e), func(err error) {
+		finished <- err
+	})
+
+	obs := &panicObservabilityService{}
+	c, err := client.New(gochan.Receiver(in),
+		client.WithPollGoroutines(1),
+		client.WithObservabilityService(obs),
+	)
+	require.NoError(t, err)
+
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+			panic("boom")
+		})
+	}()
+
+	select {
+	case err := <-finished:
+		require.True(t, protocol.IsNACK(err), "unexpected finish error %v", err)
+		require.Contains(t, err.Error(), "boom")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the message to be finished")
+	}
+
+	obs.mu.Lock()
+	defer obs.mu.Unlock()
+	require.Equal(t, []interface{}{"boom"}, obs.recovered)
+	require.Contains(t, string(obs.stacks[0]), "TestClientStartReceiverRecoversPanic")
+}
+
+func TestClientStartReceiverRecoversPanicWithResponder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := event.New()
+	e.SetID("1")
+	e.SetType("unit.test.client")
+	e.SetSource("/unit/test/client")
+
+	in := make(chan binding.Message, 1)
+	out := make(chan gochan.ChanResponderResponse, 1)
+	in <- binding.ToMessage(&e)
+
+	c, err := client.New(&gochan.Responder{In: in, Out: out}, client.WithPollGoroutines(1))
+	require.NoError(t, err)
+
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+			panic("boom")
+		})
+	}()
+
+	select {
+	case resp := <-out:
+		require.Nil(t, resp.Message)
+		require.True(t, protocol.IsNACK(resp.Result), "unexpected result %v", resp.Result)
+		require.Contains(t, resp.Result.Error(), "boom")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the response")
+	}
+}
+
+func TestClientStartReceiverRecoversPanicOutsideReceiverFn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := event.New()
+	e.SetID("1")
+	e.SetType("unit.test.client")
+	e.SetSource("/unit/test/client")
+
+	in := make(chan binding.Message, 1)
+	out := make(chan gochan.ChanResponderResponse, 1)
+	in <- binding.ToMessage(&e)
+
+	c, err := client.New(&gochan.Responder{In: in, Out: out},
+		client.WithPollGoroutines(1),
+		client.WithEventDefaulter(func(ctx context.Context, e event.Event) event.Event {
+			panic("defaulter")
+		}),
+	)
+	require.NoError(t, err)
+
+	go func() {
+		_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+			resp := e.Clone()
+			return &resp, nil
+		})
+	}()
+
+	select {
+	case resp := <-out:
+		require.Nil(t, resp.Message)
+		require.True(t, protocol.IsNACK(resp.Result), "unexpected result %v", resp.Result)
+		require.Contains(t, resp.Result.Error(), "defaulter")
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for the response")
+	}
+}
@@ -6,9 +6,11 @@
 package client
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 
 	"github.com/cloudevents/sdk-go/v2/event"
@@ -25,13 +27,14 @@ type receiverFn struct {
 
 	hasContextIn bool
 	hasEventIn   bool
+	hasDataIn    bool
 
 	hasEventOut  bool
 	hasResultOut bool
 }
 
 const (
-	inParamUsage  = "expected a function taking either no parameters, one or more of (context.Context, event.Event) ordered"
+	inParamUsage  = "expected a function taking either no parameters, one or more of (context.Context, event.Event) ordered, or (context.Context, event.Event, io.Reader)"
 	outParamUsage = "expected a function returning one or mode of (*event.Event, protocol.Result) ordered"
 )
 
@@ -39,6 +42,7 @@ var (
 	contextType  = reflect.TypeOf((*context.Context)(nil)).Elem()
 	eventType    = reflect.TypeOf((*event.Event)(nil)).Elem()
 	eventPtrType = reflect.TypeOf((*event.Event)(nil)) // want the ptr type
+	readerType   = reflect.TypeOf((*io.Reader)(nil)).Elem()
 	resultType   = reflect.TypeOf((*protocol.Result)(nil)).Elem()
 )
 
@@ -57,6 +61,10 @@ var (
 // * func(event.Event) (*event.Event, protocol.Result)
 // * func(context.Context, event.Event) *event.Event
 // * func(context.Context, event.Event) (*event.Event, protocol.Result)
+// * func(context.Context, event.Event, io.Reader)
+// * func(context.Context, event.Event, io.Reader) protocol.Result
+// * func(context.Context, event.Event, io.Reader) *event.Event
+// * func(context.Context, event.Event, io.Reader) (*event.Event, protocol.Result)
 func receiver(fn interface{}) (*receiverFn, error) {
 	fnType := reflect.TypeOf(fn)
 	if fnType.Kind() != reflect.Func {
@@ -86,6 +94,13 @@ func (r *receiverFn) invoke(ctx context.Context, e *event.Event) (*event.Event,
 		if r.hasEventIn {
 			args = append(args, reflect.ValueOf(*e))
 		}
+		if r.hasDataIn {
+			data := dataFromContext(ctx)
+			if data == nil {
+				data = bytes.NewReader(e.Data())
+			}
+			args = append(args, reflect.ValueOf(&data).Elem())
+		}
 	}
 	v := r.fnValue.Call(args)
 	var respOut protocol.Result
@@ -107,14 +122,38 @@ func (r *receiverFn) invoke(ctx context.Context, e *event.Event) (*event.Event,
 	return eOut, respOut
 }
 
+type dataKey struct{}
+
+// withData returns a context carrying the data reader of the event passed to the receiver fn.
+func withData(ctx context.Context, data io.Reader) context.Context {
+	return context.WithValue(ctx, dataKey{}, data)
+}
+
+func dataFromContext(ctx context.Context) io.Reader {
+	data, _ := ctx.Value(dataKey{}).(io.Reader)
+	return data
+}
+
 // Verifies that the inputs to a function have a valid signature
 // Valid input is to be [0, all] of
-// context.Context, event.Event in this order.
+// context.Context, event.Event in this order,
+// or context.Context, event.Event, io.Reader.
 func (r *receiverFn) validateInParamSignature(fnType reflect.Type) error {
 	r.hasContextIn = false
 	r.hasEventIn = false
+	r.hasDataIn = false
 
 	switch fnType.NumIn() {
+	case 3:
+		// has to be (context.Context, event.Event, io.Reader)
+		if fnType.In(2) != readerType {
+			return fmt.Errorf("%s; parameter 3 must be io.Reader, not %s", inParamUsage, fnType.In(2))
+		}
+		if !contextType.ConvertibleTo(fnType.In(0)) {
+			return fmt.Errorf("%s; cannot convert parameter 1 to %s from context.Context", inParamUsage, fnType.In(0))
+		}
+		r.hasDataIn = true
+		fallthrough
 	case 2:
 		// has to be (context.Context, event.Event)
 		if !eventType.ConvertibleTo(fnType.In(1)) {
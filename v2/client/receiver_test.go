@@ -8,6 +8,7 @@ package client
 import (
 	"context"
 	"errors"
+	"io"
 	"testing"
 	"time"
 
@@ -45,6 +46,10 @@ func TestReceiverFnValidTypes(t *testing.T) {
 		"Event in, Event+Result out":     func(event.Event) (*event.Event, protocol.Result) { return nil, nil },
 		"ctx+Event in, Event+Result out": func(context.Context, event.Event) (*event.Event, protocol.Result) { return nil, nil },
 
+		"ctx+Event+Reader in, no out":           func(context.Context, event.Event, io.Reader) {},
+		"ctx+Event+Reader in, error out":        func(context.Context, event.Event, io.Reader) error { return nil },
+		"ctx+Event+Reader in, Event+Result out": func(context.Context, event.Event, io.Reader) (*event.Event, protocol.Result) { return nil, nil },
+
 		"input contravariance; may accept supertype": func(event.EventReader) {},
 		"output covariance; may return subtype":      func() *myErr { return nil },
 	} {
@@ -74,6 +79,10 @@ func TestReceiverFnInvalidTypes(t *testing.T) {
 		"Event as non-ptr out":     func() event.Event { return event.Event{} },
 		"extra Event in":           func(event.Event, event.Event) {},
 		"not a function":           map[string]string(nil),
+		"Reader without context":   func(event.Event, event.Event, io.Reader) {},
+		"Reader without Event":     func(context.Context, context.Context, io.Reader) {},
+		"ReadCloser in":            func(context.Context, event.Event, io.ReadCloser) {},
+		"extra Reader in":          func(context.Context, event.Event, io.Reader, io.Reader) {},
 
 		"input covariance; must not accept subtype": func(*myCtx) {},
 	} {
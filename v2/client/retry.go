@@ -0,0 +1,48 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// permanentResult marks a result as not retriable.
+type permanentResult struct {
+	protocol.Result
+}
+
+func (p *permanentResult) Error() string {
+	return fmt.Sprintf("permanent: %s", p.Result.Error())
+}
+
+func (p *permanentResult) Unwrap() error {
+	return p.Result
+}
+
+// NewPermanentResult wraps the result returned by a receiver fn, so the invocation is not retried
+// when the client has been configured with WithReceiverRetries.
+// The ACK/NACK semantics of the wrapped result are preserved.
+func NewPermanentResult(result protocol.Result) protocol.Result {
+	if result == nil {
+		return nil
+	}
+	return &permanentResult{Result: result}
+}
+
+// IsPermanent returns true if the result, or any result it wraps, has been marked with NewPermanentResult.
+func IsPermanent(result protocol.Result) bool {
+	var p *permanentResult
+	return errors.As(result, &p)
+}
+
+// isRetriable returns true if the receiver fn can be invoked again after returning result.
+func isRetriable(result protocol.Result) bool {
+	_, malformed := isMalformedEvent(result)
+	return !protocol.IsACK(result) && !IsPermanent(result) && !malformed
+}
@@ -0,0 +1,140 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+)
+
+type retryObservabilityService struct {
+	testObservabilityService
+
+	mu       sync.Mutex
+	retries  []int
+	attempts int
+	result   error
+}
+
+func (o *retryObservabilityService) RecordRetryingInvoker(_ context.Context, _ *event.Event, attempts int, _ error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.retries = append(o.retries, attempts)
+}
+
+func (o *retryObservabilityService) RecordInvokerRetries(_ context.Context, _ *event.Event, attempts int, result error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.attempts = attempts
+	o.result = result
+}
+
+func TestClientStartReceiverWithReceiverRetries(t *testing.T) {
+	testCases := []struct {
+		name         string
+		results      []protocol.Result
+		wantAttempts int
+		wantAck      bool
+	}{
+		{
+			name:         "succeeds first time",
+			results:      []protocol.Result{nil},
+			wantAttempts: 1,
+			wantAck:      true,
+		},
+		{
+			name:         "succeeds after retries",
+			results:      []protocol.Result{protocol.ResultNACK, errors.New("transient"), protocol.ResultACK},
+			wantAttempts: 3,
+			wantAck:      true,
+		},
+		{
+			name:         "retries exhausted",
+			results:      []protocol.Result{protocol.ResultNACK, protocol.ResultNACK, protocol.ResultNACK, protocol.ResultNACK, protocol.ResultACK},
+			wantAttempts: 4,
+		},
+		{
+			name:         "permanent result stops retries",
+			results:      []protocol.Result{protocol.ResultNACK, client.NewPermanentResult(protocol.NewReceipt(false, "bad")), protocol.ResultACK},
+			wantAttempts: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			e := event.New()
+			e.SetID("1")
+			e.SetType("unit.test.client")
+			e.SetSource("/unit/test/client")
+
+			finished := make(chan error, 1)
+			in := make(chan binding.Message, 1)
+			in <- binding.WithFinish(binding.ToMessage(&e), func(err error) { finished <- err })
+
+			obs := &retryObservabilityService{}
+			c, err := client.New(gochan.Receiver(in),
+				client.WithPollGoroutines(1),
+				client.WithObservabilityService(obs),
+				client.WithReceiverRetries(&cecontext.RetryParams{
+					Strategy: cecontext.BackoffStrategyConstant,
+					Period:   time.Millisecond,
+					MaxTries: 3,
+				}),
+			)
+			require.NoError(t, err)
+
+			var invocations int32
+			go func() {
+				_ = c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+					i := atomic.AddInt32(&invocations, 1)
+					return tc.results[i-1]
+				})
+			}()
+
+			select {
+			case result := <-finished:
+				require.Equal(t, tc.wantAck, protocol.IsACK(result), "unexpected result %v", result)
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for the message to be finished")
+			}
+
+			require.Equal(t, int32(tc.wantAttempts), atomic.LoadInt32(&invocations))
+			obs.mu.Lock()
+			defer obs.mu.Unlock()
+			require.Equal(t, tc.wantAttempts, obs.attempts)
+			require.Len(t, obs.retries, tc.wantAttempts-1)
+		})
+	}
+}
+
+func TestPermanentResult(t *testing.T) {
+	require.Nil(t, client.NewPermanentResult(nil))
+	require.False(t, client.IsPermanent(protocol.ResultNACK))
+
+	nack := client.NewPermanentResult(protocol.NewReceipt(false, "bad"))
+	require.True(t, client.IsPermanent(nack))
+	require.True(t, protocol.IsNACK(nack))
+
+	ack := client.NewPermanentResult(protocol.ResultACK)
+	require.True(t, client.IsPermanent(ack))
+	require.True(t, protocol.IsACK(ack))
+}
@@ -0,0 +1,168 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Handler handles an event, optionally returning a response event.
+type Handler func(ctx context.Context, e event.Event) (*event.Event, protocol.Result)
+
+// Middleware wraps a Handler, to run code before and after it or to replace its outcome.
+type Middleware func(next Handler) Handler
+
+// Expression is a boolean expression evaluated against an event.
+// It's satisfied by the CloudEvents SQL expressions parsed by github.com/cloudevents/sdk-go/sql/v2/parser.
+type Expression interface {
+	Evaluate(event event.Event) (interface{}, error)
+}
+
+type route struct {
+	match   func(e event.Event) bool
+	handler Handler
+}
+
+// Router dispatches the received events to the first registered handler whose route matches the event.
+// Use Router.Receive as receiver fn with protocols that don't support responses, or Router.Respond
+// with the ones that do:
+//
+//	r := client.NewRouter()
+//	r.HandleType("com.example.order.created", onOrderCreated)
+//	r.HandleTypePrefix("com.example.order.", onOrderEvent)
+//	c.StartReceiver(ctx, r.Receive)
+//
+// Events matching no route are passed to the fallback handler, if any, otherwise they are
+// rejected with a permanent NACK.
+type Router struct {
+	mu          sync.RWMutex
+	routes      []route
+	fallback    Handler
+	middlewares []Middleware
+}
+
+// NewRouter returns an empty Router.
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Handle registers a handler for the events matched by the provided function.
+func (r *Router) Handle(match func(e event.Event) bool, h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = append(r.routes, route{match: match, handler: h})
+}
+
+// HandleType registers a handler for the events with the provided type.
+func (r *Router) HandleType(eventType string, h Handler) {
+	r.Handle(func(e event.Event) bool {
+		return e.Type() == eventType
+	}, h)
+}
+
+// HandleTypePrefix registers a handler for the events whose type starts with the provided prefix.
+func (r *Router) HandleTypePrefix(prefix string, h Handler) {
+	r.Handle(func(e event.Event) bool {
+		return strings.HasPrefix(e.Type(), prefix)
+	}, h)
+}
+
+// HandleTypePattern registers a handler for the events whose type matches the provided glob pattern,
+// using the syntax of path.Match. It returns an error if the pattern is malformed.
+func (r *Router) HandleTypePattern(pattern string, h Handler) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid type pattern %q: %w", pattern, err)
+	}
+	r.Handle(func(e event.Event) bool {
+		ok, _ := path.Match(pattern, e.Type())
+		return ok
+	}, h)
+	return nil
+}
+
+// HandleSourcePattern registers a handler for the events whose source matches the provided glob pattern,
+// using the syntax of path.Match: `*` doesn't match `/`, so "/orders/*" matches "/orders/1" but not "/orders/1/items".
+// It returns an error if the pattern is malformed.
+func (r *Router) HandleSourcePattern(pattern string, h Handler) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid source pattern %q: %w", pattern, err)
+	}
+	r.Handle(func(e event.Event) bool {
+		ok, _ := path.Match(pattern, e.Source())
+		return ok
+	}, h)
+	return nil
+}
+
+// HandleExpression registers a handler for the events for which the provided expression evaluates to true.
+// Evaluation errors and non boolean results don't match.
+func (r *Router) HandleExpression(expr Expression, h Handler) {
+	r.Handle(func(e event.Event) bool {
+		res, err := expr.Evaluate(e)
+		if err != nil {
+			return false
+		}
+		b, ok := res.(bool)
+		return ok && b
+	}, h)
+}
+
+// Fallback sets the handler invoked for the events matching no route.
+func (r *Router) Fallback(h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = h
+}
+
+// Use appends middlewares wrapping the matched handlers, including the fallback one.
+// The first middleware is the outermost one.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Respond dispatches the event to the matching handler, returning its response event.
+// It can be used as receiver fn for protocols supporting responses.
+func (r *Router) Respond(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+	r.mu.RLock()
+	h := r.fallback
+	for _, rt := range r.routes {
+		if rt.match(e) {
+			h = rt.handler
+			break
+		}
+	}
+	middlewares := r.middlewares
+	r.mu.RUnlock()
+
+	if h == nil {
+		return nil, NewPermanentResult(protocol.NewReceipt(false, "no handler registered for event type %q from source %q", e.Type(), e.Source()))
+	}
+	return chain(h, middlewares)(ctx, e)
+}
+
+// Receive dispatches the event to the matching handler, dropping any response event.
+// It can be used as receiver fn for any protocol.
+func (r *Router) Receive(ctx context.Context, e event.Event) protocol.Result {
+	_, result := r.Respond(ctx, e)
+	return result
+}
+
+// chain wraps h with the middlewares, the first one being the outermost.
+func chain(h Handler, middlewares []Middleware) Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
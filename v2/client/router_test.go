@@ -0,0 +1,115 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+type typeIsExpression string
+
+func (t typeIsExpression) Evaluate(e event.Event) (interface{}, error) {
+	if t == "error" {
+		return nil, errors.New("evaluation error")
+	}
+	return e.Type() == string(t), nil
+}
+
+func routeTo(name string, got *string) client.Handler {
+	return func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+		*got = name
+		return nil, nil
+	}
+}
+
+func TestRouter(t *testing.T) {
+	var got string
+	r := client.NewRouter()
+	r.HandleExpression(typeIsExpression("error"), routeTo("error", &got))
+	r.HandleExpression(typeIsExpression("com.example.sql"), routeTo("expression", &got))
+	r.HandleType("com.example.order.created", routeTo("exact", &got))
+	r.HandleTypePrefix("com.example.order.", routeTo("prefix", &got))
+	require.NoError(t, r.HandleTypePattern("com.*.payment.*", routeTo("type pattern", &got)))
+	require.NoError(t, r.HandleSourcePattern("/tenants/*/events", routeTo("source pattern", &got)))
+	require.Error(t, r.HandleTypePattern("[", routeTo("invalid", &got)))
+
+	testCases := []struct {
+		eventType string
+		source    string
+		want      string
+	}{
+		{eventType: "com.example.order.created", source: "/orders", want: "exact"},
+		{eventType: "com.example.order.deleted", source: "/orders", want: "prefix"},
+		{eventType: "com.acme.payment.done", source: "/payments", want: "type pattern"},
+		{eventType: "com.example.other", source: "/tenants/1/events", want: "source pattern"},
+		{eventType: "com.example.sql", source: "/sql", want: "expression"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.want, func(t *testing.T) {
+			got = ""
+			e := event.New()
+			e.SetType(tc.eventType)
+			e.SetSource(tc.source)
+			require.True(t, protocol.IsACK(r.Receive(context.Background(), e)))
+			require.Equal(t, tc.want, got)
+		})
+	}
+
+	t.Run("no match without fallback", func(t *testing.T) {
+		e := event.New()
+		e.SetType("com.example.unknown")
+		result := r.Receive(context.Background(), e)
+		require.True(t, protocol.IsNACK(result))
+		require.True(t, client.IsPermanent(result))
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		got = ""
+		r.Fallback(routeTo("fallback", &got))
+		e := event.New()
+		e.SetType("com.example.unknown")
+		require.True(t, protocol.IsACK(r.Receive(context.Background(), e)))
+		require.Equal(t, "fallback", got)
+	})
+}
+
+func TestRouterMiddlewares(t *testing.T) {
+	var calls []string
+	record := func(name string) client.Middleware {
+		return func(next client.Handler) client.Handler {
+			return func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+				calls = append(calls, name+" before")
+				resp, result := next(ctx, e)
+				calls = append(calls, name+" after")
+				return resp, result
+			}
+		}
+	}
+
+	r := client.NewRouter()
+	r.Use(record("outer"), record("inner"))
+	r.HandleType("com.example.request", func(ctx context.Context, e event.Event) (*event.Event, protocol.Result) {
+		calls = append(calls, "handler")
+		resp := event.New()
+		resp.SetType("com.example.response")
+		return &resp, nil
+	})
+
+	e := event.New()
+	e.SetType("com.example.request")
+	resp, result := r.Respond(context.Background(), e)
+	require.True(t, protocol.IsACK(result))
+	require.Equal(t, "com.example.response", resp.Type())
+	require.Equal(t, []string{"outer before", "inner before", "handler", "inner after", "outer after"}, calls)
+}
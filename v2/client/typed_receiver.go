@@ -0,0 +1,54 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// TypedReceiveFn is the signature of a fn receiving the event data decoded as T.
+type TypedReceiveFn[T any] func(ctx context.Context, e event.Event, data T) error
+
+// StartTypedReceiver works as Client.StartReceiver, but the data of each event is decoded as T,
+// using the datacodec registered for the event datacontenttype, before invoking fn.
+// Events with no data are passed with the zero value of T.
+// Events whose data cannot be decoded are handled as malformed events, without invoking fn.
+func StartTypedReceiver[T any](ctx context.Context, c Client, fn TypedReceiveFn[T]) error {
+	if fn == nil {
+		return errors.New("must pass a function to handle events")
+	}
+	return c.StartReceiver(ctx, func(ctx context.Context, e event.Event) protocol.Result {
+		var data T
+		if err := e.DataAs(&data); err != nil {
+			return &malformedEventError{err: fmt.Errorf("failed to decode %q data as %T: %w", e.DataContentType(), data, err)}
+		}
+		return fn(ctx, e, data)
+	})
+}
+
+// malformedEventError is returned by a receiver fn to signal that the event is malformed,
+// so that the invoker handles it as such.
+type malformedEventError struct {
+	err error
+}
+
+func (m *malformedEventError) Error() string {
+	return m.err.Error()
+}
+
+func (m *malformedEventError) Unwrap() error {
+	return m.err
+}
+
+func isMalformedEvent(result protocol.Result) (*malformedEventError, bool) {
+	var m *malformedEventError
+	return m, errors.As(result, &m)
+}
@@ -0,0 +1,129 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package client_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/client"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
+)
+
+type typedData struct {
+	Message  string `json:"message" xml:"message"`
+	Sequence int    `json:"sequence" xml:"sequence"`
+}
+
+func TestStartTypedReceiver(t *testing.T) {
+	newEvent := func(contentType string, data []byte) event.Event {
+		e := event.New()
+		e.SetID("1")
+		e.SetType("unit.test.client")
+		e.SetSource("/unit/test/client")
+		if data != nil {
+			_ = e.SetData(contentType, data)
+		}
+		return e
+	}
+
+	testCases := []struct {
+		name        string
+		event       event.Event
+		opts        []client.Option
+		fnResult    error
+		want        *typedData
+		wantAck     bool
+		wantInvoked bool
+	}{
+		{
+			name:        "json",
+			event:       newEvent(event.ApplicationJSON, []byte(`{"message":"hello","sequence":42}`)),
+			want:        &typedData{Message: "hello", Sequence: 42},
+			wantAck:     true,
+			wantInvoked: true,
+		},
+		{
+			name:        "xml",
+			event:       newEvent(event.ApplicationXML, []byte(`<typedData><message>hello</message><sequence>42</sequence></typedData>`)),
+			want:        &typedData{Message: "hello", Sequence: 42},
+			wantAck:     true,
+			wantInvoked: true,
+		},
+		{
+			name:        "no data",
+			event:       newEvent("", nil),
+			want:        &typedData{},
+			wantAck:     true,
+			wantInvoked: true,
+		},
+		{
+			name:        "fn result",
+			event:       newEvent(event.ApplicationJSON, []byte(`{"message":"hello"}`)),
+			fnResult:    protocol.ResultNACK,
+			want:        &typedData{Message: "hello"},
+			wantInvoked: true,
+		},
+		{
+			name:  "undecodable data",
+			event: newEvent(event.ApplicationJSON, []byte(`{"message":42}`)),
+		},
+		{
+			name:    "undecodable data with ack malformed",
+			event:   newEvent(event.ApplicationJSON, []byte(`not json`)),
+			opts:    []client.Option{client.WithAckMalformedEvent()},
+			wantAck: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			finished := make(chan error, 1)
+			in := make(chan binding.Message, 1)
+			in <- binding.WithFinish(binding.ToMessage(&tc.event), func(err error) { finished <- err })
+
+			c, err := client.New(gochan.Receiver(in), append(tc.opts, client.WithPollGoroutines(1))...)
+			require.NoError(t, err)
+
+			got := make(chan typedData, 1)
+			go func() {
+				_ = client.StartTypedReceiver(ctx, c, func(ctx context.Context, e event.Event, data typedData) error {
+					got <- data
+					return tc.fnResult
+				})
+			}()
+
+			select {
+			case result := <-finished:
+				require.Equal(t, tc.wantAck, protocol.IsACK(result), "unexpected result %v", result)
+			case <-time.After(time.Second):
+				t.Fatal("timed out waiting for the message to be finished")
+			}
+
+			if !tc.wantInvoked {
+				require.Len(t, got, 0)
+				return
+			}
+			require.Len(t, got, 1)
+			require.Equal(t, *tc.want, <-got)
+		})
+	}
+}
+
+func TestStartTypedReceiverNilFn(t *testing.T) {
+	c, err := client.New(gochan.New())
+	require.NoError(t, err)
+	require.Error(t, client.StartTypedReceiver[typedData](context.Background(), c, nil))
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"errors"
 	"math"
+	"math/rand/v2"
 	"time"
 )
 
@@ -21,8 +22,40 @@ const (
 	BackoffStrategyExponential = "exponential"
 )
 
+// Jitter randomizes the interval between retries, so that clients failing at the same time
+// don't retry all at the same moment.
+type Jitter string
+
+const (
+	// JitterNone doesn't randomize the interval between retries.
+	JitterNone = "none"
+	// JitterFull picks the interval between retries uniformly in [0, interval).
+	JitterFull = "full"
+	// JitterDecorrelated picks the interval between retries uniformly in [Period, 3 * previous interval],
+	// where the previous interval is the one computed by the strategy for the previous retry.
+	JitterDecorrelated = "decorrelated"
+)
+
+// BackoffPolicy computes the interval between retries.
+// It can be set in RetryParams to plug a backoff algorithm other than the built-in strategies.
+type BackoffPolicy interface {
+	// BackoffFor returns the time to wait before the retry number `tries`.
+	BackoffFor(tries int) time.Duration
+}
+
+// BackoffPolicyFunc is a function implementing BackoffPolicy.
+type BackoffPolicyFunc func(tries int) time.Duration
+
+// BackoffFor implements BackoffPolicy.
+func (f BackoffPolicyFunc) BackoffFor(tries int) time.Duration {
+	return f(tries)
+}
+
 var DefaultRetryParams = RetryParams{Strategy: BackoffStrategyNone}
 
+// ErrRetryDeadlineExceeded is returned by BackoffSince when waiting for the next retry would exceed RetryParams.MaxElapsedTime.
+var ErrRetryDeadlineExceeded = errors.New("retry deadline exceeded")
+
 // RetryParams holds parameters applied to retries
 type RetryParams struct {
 	// Strategy is the backoff strategy to applies between retries
@@ -37,20 +70,77 @@ type RetryParams struct {
 	// - for linear strategy: interval between retries = Period * retries
 	// - for exponential strategy: interval between retries = Period * retries^2
 	Period time.Duration
+
+	// Policy, when set, computes the interval between retries in place of Strategy.
+	Policy BackoffPolicy
+
+	// Jitter is the randomization applied to the interval between retries. Defaults to JitterNone.
+	Jitter Jitter
+
+	// MaxDelay, when greater than zero, caps the interval between retries.
+	MaxDelay time.Duration
+
+	// MaxElapsedTime, when greater than zero, is the maximum time spent since the first try
+	// after which no retry is attempted anymore. It is enforced by BackoffSince.
+	MaxElapsedTime time.Duration
+}
+
+// IsRetrying reports whether the parameters configure any retry.
+func (r *RetryParams) IsRetrying() bool {
+	if r.Policy != nil {
+		return true
+	}
+	switch r.Strategy {
+	case BackoffStrategyConstant, BackoffStrategyLinear, BackoffStrategyExponential:
+		return true
+	default:
+		return false
+	}
 }
 
 // BackoffFor tries will return the time duration that should be used for this
-// current try count.
+// current try count, with the jitter and the cap applied.
 // `tries` is assumed to be the number of times the caller has already retried.
 func (r *RetryParams) BackoffFor(tries int) time.Duration {
+	d := r.backoffFor(tries)
+	switch r.Jitter {
+	case JitterFull:
+		if d > 0 {
+			d = rand.N(d)
+		}
+	case JitterDecorrelated:
+		upper := time.Duration(math.MaxInt64)
+		if prev := r.backoffFor(tries - 1); prev < upper/3 {
+			upper = 3 * prev
+		}
+		if upper > r.Period {
+			d = r.Period + rand.N(upper-r.Period+1)
+		} else {
+			d = r.Period
+		}
+	}
+	if r.MaxDelay > 0 && d > r.MaxDelay {
+		d = r.MaxDelay
+	}
+	return d
+}
+
+// backoffFor returns the interval computed by the policy or the strategy, without jitter and cap.
+func (r *RetryParams) backoffFor(tries int) time.Duration {
+	if r.Policy != nil {
+		return r.Policy.BackoffFor(tries)
+	}
 	switch r.Strategy {
 	case BackoffStrategyConstant:
 		return r.Period
 	case BackoffStrategyLinear:
 		return r.Period * time.Duration(tries)
 	case BackoffStrategyExponential:
-		exp := math.Exp2(float64(tries))
-		return r.Period * time.Duration(exp)
+		exp := math.Exp2(float64(tries)) * float64(r.Period)
+		if exp >= math.MaxInt64 {
+			return time.Duration(math.MaxInt64)
+		}
+		return time.Duration(exp)
 	case BackoffStrategyNone:
 		fallthrough // default
 	default:
@@ -61,16 +151,35 @@ func (r *RetryParams) BackoffFor(tries int) time.Duration {
 // Backoff is a blocking call to wait for the correct amount of time for the retry.
 // `tries` is assumed to be the number of times the caller has already retried.
 func (r *RetryParams) Backoff(ctx context.Context, tries int) error {
+	return r.BackoffSince(ctx, time.Time{}, tries, 0)
+}
+
+// BackoffSince is like Backoff, but it also enforces MaxElapsedTime counting from `start`, the time of the
+// first try, and it waits at least `minDelay` (e.g. the delay requested by the peer).
+// A zero `start` disables the MaxElapsedTime check.
+func (r *RetryParams) BackoffSince(ctx context.Context, start time.Time, tries int, minDelay time.Duration) error {
 	if tries > r.MaxTries {
 		return errors.New("too many retries")
 	}
-	ticker := time.NewTicker(r.BackoffFor(tries))
+	d := r.BackoffFor(tries)
+	if d < minDelay {
+		d = minDelay
+	}
+	if r.MaxElapsedTime > 0 && !start.IsZero() && time.Since(start)+d > r.MaxElapsedTime {
+		return ErrRetryDeadlineExceeded
+	}
+	if d <= 0 {
+		if ctx.Err() != nil {
+			return errors.New("context has been cancelled")
+		}
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
 	select {
 	case <-ctx.Done():
-		ticker.Stop()
 		return errors.New("context has been cancelled")
-	case <-ticker.C:
-		ticker.Stop()
+	case <-timer.C:
 	}
 	return nil
 }
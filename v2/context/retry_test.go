@@ -117,3 +117,85 @@ func TestRetryParams_BackoffFor(t *testing.T) {
 		})
 	}
 }
+
+func TestRetryParams_BackoffFor_JitterAndCap(t *testing.T) {
+	tests := map[string]struct {
+		rp       *RetryParams
+		tries    int
+		min, max time.Duration
+	}{
+		"exponential capped": {
+			rp:    &RetryParams{Strategy: BackoffStrategyExponential, Period: time.Second, MaxDelay: 10 * time.Second},
+			tries: 5,
+			min:   10 * time.Second,
+			max:   10 * time.Second,
+		},
+		"exponential overflow capped": {
+			rp:    &RetryParams{Strategy: BackoffStrategyExponential, Period: time.Second, MaxDelay: time.Minute},
+			tries: 100,
+			min:   time.Minute,
+			max:   time.Minute,
+		},
+		"full jitter": {
+			rp:    &RetryParams{Strategy: BackoffStrategyExponential, Period: time.Second, Jitter: JitterFull},
+			tries: 3,
+			min:   0,
+			max:   8*time.Second - 1,
+		},
+		"full jitter capped": {
+			rp:    &RetryParams{Strategy: BackoffStrategyExponential, Period: time.Second, Jitter: JitterFull, MaxDelay: 2 * time.Second},
+			tries: 3,
+			min:   0,
+			max:   2 * time.Second,
+		},
+		"decorrelated jitter": {
+			rp:    &RetryParams{Strategy: BackoffStrategyExponential, Period: time.Second, Jitter: JitterDecorrelated},
+			tries: 3,
+			min:   time.Second,
+			max:   12 * time.Second, // 3 * 2^2
+		},
+		"policy": {
+			rp: &RetryParams{Policy: BackoffPolicyFunc(func(tries int) time.Duration {
+				return time.Duration(tries) * time.Hour
+			}), MaxDelay: 90 * time.Minute},
+			tries: 2,
+			min:   90 * time.Minute,
+			max:   90 * time.Minute,
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := tc.rp.BackoffFor(tc.tries); got < tc.min || got > tc.max {
+					t.Fatalf("BackoffFor() = %v, want in [%v, %v]", got, tc.min, tc.max)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryParams_BackoffSince(t *testing.T) {
+	rp := &RetryParams{Strategy: BackoffStrategyConstant, MaxTries: 10, Period: time.Millisecond, MaxElapsedTime: time.Second}
+
+	if err := rp.BackoffSince(context.Background(), time.Now(), 1, 0); err != nil {
+		t.Errorf("BackoffSince() unexpected error = %v", err)
+	}
+	if err := rp.BackoffSince(context.Background(), time.Now().Add(-time.Second), 1, 0); err != ErrRetryDeadlineExceeded {
+		t.Errorf("BackoffSince() error = %v, want %v", err, ErrRetryDeadlineExceeded)
+	}
+	if err := rp.BackoffSince(context.Background(), time.Now(), 1, 2*time.Second); err != ErrRetryDeadlineExceeded {
+		t.Errorf("BackoffSince() error = %v, want %v", err, ErrRetryDeadlineExceeded)
+	}
+
+	start := time.Now()
+	if err := rp.BackoffSince(context.Background(), time.Time{}, 1, 20*time.Millisecond); err != nil {
+		t.Errorf("BackoffSince() unexpected error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("BackoffSince() waited %v, want at least %v", elapsed, 20*time.Millisecond)
+	}
+
+	if !rp.IsRetrying() || (&RetryParams{Strategy: BackoffStrategyNone}).IsRetrying() {
+		t.Errorf("IsRetrying() returned unexpected value")
+	}
+}
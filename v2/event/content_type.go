@@ -12,6 +12,8 @@ const (
 	ApplicationXML                  = "application/xml"
 	ApplicationCloudEventsJSON      = "application/cloudevents+json"
 	ApplicationCloudEventsBatchJSON = "application/cloudevents-batch+json"
+	ApplicationCloudEventsXML       = "application/cloudevents+xml"
+	ApplicationCloudEventsBatchXML  = "application/cloudevents-batch+xml"
 )
 
 // isJSON returns true if the content type is a JSON type.
@@ -57,3 +59,17 @@ func StringOfApplicationCloudEventsBatchJSON() *string {
 	a := ApplicationCloudEventsBatchJSON
 	return &a
 }
+
+// StringOfApplicationCloudEventsXML returns a string pointer to
+// "application/cloudevents+xml"
+func StringOfApplicationCloudEventsXML() *string {
+	a := ApplicationCloudEventsXML
+	return &a
+}
+
+// StringOfApplicationCloudEventsBatchXML returns a string pointer to
+// "application/cloudevents-batch+xml"
+func StringOfApplicationCloudEventsBatchXML() *string {
+	a := ApplicationCloudEventsBatchXML
+	return &a
+}
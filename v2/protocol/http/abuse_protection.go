@@ -7,12 +7,16 @@ package http
 
 import (
 	"context"
-	cecontext "github.com/cloudevents/sdk-go/v2/context"
-	"go.uber.org/zap"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 	"time"
+
+	"go.uber.org/zap"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
 )
 
 type WebhookConfig struct {
@@ -27,8 +31,6 @@ const (
 	DefaultTimeout     = time.Second * 600
 )
 
-// TODO: implement rate limiting.
-// Throttling is indicated by requests being rejected using HTTP status code 429 Too Many Requests.
 // TODO: use this if Webhook Request Origin has been turned on.
 // Inbound requests should be rejected if Allowed Origins is required by SDK.
 
@@ -55,8 +57,8 @@ func (p *Protocol) OptionsHandler(rw http.ResponseWriter, req *http.Request) {
 		allowedRateRequired = true
 	}
 
-	if p.WebhookConfig.AllowedRate != nil {
-		headers.Set("WebHook-Allowed-Rate", strconv.Itoa(*p.WebhookConfig.AllowedRate))
+	if allowedRate, ok := p.allowedRate(); ok {
+		headers.Set("WebHook-Allowed-Rate", strconv.Itoa(allowedRate))
 	} else if allowedRateRequired {
 		headers.Set("WebHook-Allowed-Rate", strconv.Itoa(DefaultAllowedRate))
 	}
@@ -69,31 +71,18 @@ func (p *Protocol) OptionsHandler(rw http.ResponseWriter, req *http.Request) {
 
 	cb := req.Header.Get("WebHook-Request-Callback")
 	if cb != "" {
+		// The permission is granted asynchronously, by a GET request on the callback.
 		if p.WebhookConfig.AutoACKCallback {
 			go func() {
-				reqAck, err := http.NewRequest(http.MethodPost, cb, nil)
-				if err != nil {
-					cecontext.LoggerFrom(req.Context()).Errorw("OPTIONS handler failed to create http request attempting to ack callback.", zap.Error(err), zap.String("callback", cb))
-					return
-				}
-
-				// Write out the headers.
-				for k := range headers {
-					reqAck.Header.Set(k, headers.Get(k))
-				}
-
-				_, err = http.DefaultClient.Do(reqAck)
-				if err != nil {
-					cecontext.LoggerFrom(req.Context()).Errorw("OPTIONS handler failed to ack callback.", zap.Error(err), zap.String("callback", cb))
-					return
+				ctx := context.WithoutCancel(req.Context())
+				if err := p.confirmWebhookCallback(ctx, cb, headers); err != nil {
+					cecontext.LoggerFrom(ctx).Errorw("OPTIONS handler failed to ack callback.", zap.Error(err), zap.String("callback", cb))
 				}
 			}()
-			return
 		} else {
 			cecontext.LoggerFrom(req.Context()).Infof("ACTION REQUIRED: Please validate web hook request callback: %q", cb)
-			// TODO: what to do pending https://github.com/cloudevents/spec/issues/617
-			return
 		}
+		return
 	}
 
 	// Write out the headers.
@@ -102,6 +91,59 @@ func (p *Protocol) OptionsHandler(rw http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// ConfirmWebhookCallback grants origin the permission to send events, requested by a
+// validation handshake with the WebHook-Request-Callback header, e.g. after it has been
+// validated manually when WebhookConfig.AutoACKCallback is false.
+func (p *Protocol) ConfirmWebhookCallback(ctx context.Context, callback string, origin string) error {
+	headers := make(http.Header)
+	headers.Set("WebHook-Allowed-Origin", origin)
+	if allowedRate, ok := p.allowedRate(); ok {
+		headers.Set("WebHook-Allowed-Rate", strconv.Itoa(allowedRate))
+	} else {
+		headers.Set("WebHook-Allowed-Rate", strconv.Itoa(DefaultAllowedRate))
+	}
+	return p.confirmWebhookCallback(ctx, callback, headers)
+}
+
+func (p *Protocol) confirmWebhookCallback(ctx context.Context, callback string, headers http.Header) error {
+	reqAck, err := http.NewRequestWithContext(ctx, http.MethodGet, callback, nil)
+	if err != nil {
+		return err
+	}
+	for k := range headers {
+		reqAck.Header.Set(k, headers.Get(k))
+	}
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(reqAck)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("callback %s returned status %d", callback, resp.StatusCode)
+	}
+	return nil
+}
+
+// allowedRate returns the rate advertised to the senders, in requests per minute: the one of
+// the WebhookConfig if set, otherwise the one of the RateLimiter if it is an AllowedRateLimiter.
+func (p *Protocol) allowedRate() (int, bool) {
+	if p.WebhookConfig != nil && p.WebhookConfig.AllowedRate != nil {
+		return *p.WebhookConfig.AllowedRate, true
+	}
+	if limiter, ok := p.limiter.(AllowedRateLimiter); ok {
+		if allowedRate := limiter.AllowedRate(); allowedRate > 0 {
+			return allowedRate, true
+		}
+	}
+	return 0, false
+}
+
 func (p *Protocol) ValidateRequestOrigin(req *http.Request) (string, bool) {
 	return p.validateOrigin(req.Header.Get("WebHook-Request-Origin"))
 }
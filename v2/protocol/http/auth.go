@@ -0,0 +1,310 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// HeaderAuthorization carries the bearer token of the request.
+	HeaderAuthorization = "Authorization"
+	// HeaderWebhookSignature carries the HMAC-SHA256 signatures of the request, in the form
+	// "t=<unix timestamp>,n=<nonce>,v1=<hex signature>", where the signature is computed on
+	// "<unix timestamp>.<nonce>.", followed by a "<name>:<value>\n" line for each of the
+	// Content-Type and ce- headers, in lower case and sorted by name, and by the body.
+	// There can be several v1 signatures.
+	HeaderWebhookSignature = "WebHook-Signature"
+
+	// DefaultSignatureTolerance is the default maximum age of a signed request,
+	// and the window in which replayed signatures are detected.
+	DefaultSignatureTolerance = time.Minute * 5
+
+	// DefaultSignatureMaxBodySize is the default maximum size of the body of a signed request.
+	DefaultSignatureMaxBodySize = 4 << 20
+)
+
+// RequestTransformer modifies the outbound HTTP requests right before they are sent,
+// e.g. to authenticate them. It's invoked on every attempt of a request.
+type RequestTransformer func(req *http.Request) error
+
+// transformRequest applies the RequestTransformers set with WithRequestTransformer to req.
+func (p *Protocol) transformRequest(req *http.Request) error {
+	for _, t := range p.transformers {
+		if err := t(req); err != nil {
+			return fmt.Errorf("transforming request: %w", err)
+		}
+	}
+	return nil
+}
+
+// KeyRing holds the secrets authenticating the requests, i.e. bearer tokens or HMAC keys.
+// The first key is used by the sender, while the receiver accepts any of them, so the
+// keys can be rotated without downtime: add the new key to the receiver, rotate the
+// sender, then remove the old key from the receiver.
+type KeyRing struct {
+	mu   sync.RWMutex
+	keys [][]byte
+}
+
+// NewKeyRing returns a KeyRing holding keys.
+func NewKeyRing(keys ...[]byte) *KeyRing {
+	k := &KeyRing{}
+	k.Rotate(keys...)
+	return k
+}
+
+// Rotate replaces the keys of the KeyRing.
+func (k *KeyRing) Rotate(keys ...[]byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys = keys
+}
+
+// Keys returns the keys of the KeyRing.
+func (k *KeyRing) Keys() [][]byte {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.keys
+}
+
+func (k *KeyRing) primary() ([]byte, error) {
+	keys := k.Keys()
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no key in key ring")
+	}
+	return keys[0], nil
+}
+
+// BearerToken returns a RequestTransformer sending the first key of keys as bearer token.
+func BearerToken(keys *KeyRing) RequestTransformer {
+	return func(req *http.Request) error {
+		token, err := keys.primary()
+		if err != nil {
+			return err
+		}
+		req.Header.Set(HeaderAuthorization, "Bearer "+string(token))
+		return nil
+	}
+}
+
+// HMACSignature returns a RequestTransformer signing the request body with the first key of keys.
+func HMACSignature(keys *KeyRing) RequestTransformer {
+	return func(req *http.Request) error {
+		key, err := keys.primary()
+		if err != nil {
+			return err
+		}
+		body, err := requestBody(req)
+		if err != nil {
+			return err
+		}
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		timestamp, nonce := strconv.FormatInt(time.Now().Unix(), 10), hex.EncodeToString(b)
+		signature := sign(key, timestamp, nonce, req.Header, body)
+		req.Header.Set(HeaderWebhookSignature, "t="+timestamp+",n="+nonce+",v1="+hex.EncodeToString(signature))
+		return nil
+	}
+}
+
+// requestBody returns the body of req, keeping it readable.
+func requestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+	resetBody(req, body)
+	return body, nil
+}
+
+func sign(key []byte, timestamp, nonce string, header http.Header, body []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(timestamp + "." + nonce + "."))
+	var names []string
+	for name := range header {
+		if name = strings.ToLower(name); name == "content-type" || strings.HasPrefix(name, "ce-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		mac.Write([]byte(name + ":" + strings.Join(header.Values(name), ",") + "\n"))
+	}
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// BearerTokenAuth returns a Middleware accepting the requests with any of keys as bearer token.
+// Requests without bearer token are rejected with 401 Unauthorized, requests with an invalid
+// one with 403 Forbidden.
+func BearerTokenAuth(keys *KeyRing) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			scheme, token, _ := strings.Cut(req.Header.Get(HeaderAuthorization), " ")
+			if !strings.EqualFold(scheme, "Bearer") || token == "" {
+				rw.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(rw, "missing bearer token", http.StatusUnauthorized)
+				return
+			}
+			for _, key := range keys.Keys() {
+				if subtle.ConstantTimeCompare([]byte(token), key) == 1 {
+					next.ServeHTTP(rw, req)
+					return
+				}
+			}
+			http.Error(rw, "invalid bearer token", http.StatusForbidden)
+		})
+	}
+}
+
+// HMACSignatureAuth returns a Middleware accepting the requests signed with any of keys,
+// in the last tolerance, and rejecting replayed nonces. If tolerance is 0,
+// DefaultSignatureTolerance is used.
+// Requests without signature are rejected with 401 Unauthorized, requests with an invalid,
+// expired or replayed one with 403 Forbidden.
+// The body of the requests is buffered to verify the signature, up to maxBodySize bytes:
+// the requests with a larger body are rejected with 413 Request Entity Too Large.
+// If maxBodySize is 0, DefaultSignatureMaxBodySize is used.
+func HMACSignatureAuth(keys *KeyRing, tolerance time.Duration, maxBodySize int64) Middleware {
+	if tolerance == 0 {
+		tolerance = DefaultSignatureTolerance
+	}
+	if maxBodySize == 0 {
+		maxBodySize = DefaultSignatureMaxBodySize
+	}
+	replays := &replayCache{seen: make(map[string]time.Time)}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			header := req.Header.Get(HeaderWebhookSignature)
+			if header == "" {
+				http.Error(rw, "missing signature", http.StatusUnauthorized)
+				return
+			}
+			timestamp, nonce, signatures := parseSignature(header)
+			unix, err := strconv.ParseInt(timestamp, 10, 64)
+			if err != nil || nonce == "" || len(signatures) == 0 {
+				http.Error(rw, "malformed signature", http.StatusForbidden)
+				return
+			}
+			signedAt := time.Unix(unix, 0)
+			if age := time.Since(signedAt); age > tolerance || age < -tolerance {
+				http.Error(rw, "expired signature", http.StatusForbidden)
+				return
+			}
+
+			if req.ContentLength > maxBodySize {
+				http.Error(rw, fmt.Sprintf("body larger than %d bytes", maxBodySize), http.StatusRequestEntityTooLarge)
+				return
+			}
+			body, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxBodySize))
+			if err != nil {
+				var maxBytesErr *http.MaxBytesError
+				if errors.As(err, &maxBytesErr) {
+					http.Error(rw, fmt.Sprintf("body larger than %d bytes", maxBodySize), http.StatusRequestEntityTooLarge)
+					return
+				}
+				http.Error(rw, "reading body: "+err.Error(), http.StatusBadRequest)
+				return
+			}
+			_ = req.Body.Close()
+			resetBody(req, body)
+
+			if !verify(keys.Keys(), timestamp, nonce, req.Header, body, signatures) {
+				http.Error(rw, "invalid signature", http.StatusForbidden)
+				return
+			}
+			if !replays.add(nonce, signedAt.Add(tolerance)) {
+				http.Error(rw, "replayed signature", http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
+
+// parseSignature parses the timestamp, the nonce and the v1 signatures of a HeaderWebhookSignature.
+func parseSignature(header string) (timestamp, nonce string, signatures []string) {
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "n":
+			nonce = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	return timestamp, nonce, signatures
+}
+
+// verify returns whether any of signatures matches the signature of the request with any of keys.
+func verify(keys [][]byte, timestamp, nonce string, header http.Header, body []byte, signatures []string) bool {
+	for _, key := range keys {
+		expected := sign(key, timestamp, nonce, header, body)
+		for _, signature := range signatures {
+			decoded, err := hex.DecodeString(signature)
+			if err == nil && hmac.Equal(decoded, expected) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// replayCache records the nonces seen until they expire.
+type replayCache struct {
+	mu     sync.Mutex
+	seen   map[string]time.Time
+	purged time.Time
+}
+
+// add records nonce, returning false if it has already been seen.
+func (c *replayCache) add(nonce string, expiry time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now := time.Now(); now.Sub(c.purged) > time.Second {
+		for s, e := range c.seen {
+			if now.After(e) {
+				delete(c.seen, s)
+			}
+		}
+		c.purged = now
+	}
+	if _, ok := c.seen[nonce]; ok {
+		return false
+	}
+	c.seen[nonce] = expiry
+	return true
+}
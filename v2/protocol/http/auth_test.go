@@ -0,0 +1,185 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+// requireStatus checks the result of sending is a Result with the status code.
+func requireStatus(t *testing.T, status int, result error) {
+	t.Helper()
+	if status/100 == 2 {
+		require.True(t, protocol.IsACK(result), "unexpected result %v", result)
+		return
+	}
+	var res *Result
+	require.True(t, protocol.ResultAs(result, &res), "unexpected result %v", result)
+	require.Equal(t, status, res.StatusCode)
+}
+
+// openAuthReceiver opens a receiver protected by middleware, returning its URL.
+func openAuthReceiver(t *testing.T, ctx context.Context, middleware Middleware) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver, err := New(WithListener(listener), WithMiddleware(middleware))
+	require.NoError(t, err)
+	_, done := openAndRespond(t, ctx, receiver)
+	t.Cleanup(func() {
+		require.NoError(t, <-done)
+	})
+	return "http://" + listener.Addr().String()
+}
+
+func TestBearerToken(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiverKeys := NewKeyRing([]byte("token-1"))
+	target := openAuthReceiver(t, ctx, BearerTokenAuth(receiverKeys))
+	send := func(opts ...Option) error {
+		sender, err := New(append(opts, WithTarget(target))...)
+		require.NoError(t, err)
+		return sender.Send(ctx, test.MinMessage())
+	}
+
+	senderKeys := NewKeyRing([]byte("token-1"))
+	requireStatus(t, http.StatusOK, send(WithRequestTransformer(BearerToken(senderKeys))))
+	requireStatus(t, http.StatusUnauthorized, send())
+	requireStatus(t, http.StatusForbidden, send(WithRequestTransformer(BearerToken(NewKeyRing([]byte("token-2"))))))
+	// The authentication scheme is case insensitive
+	requireStatus(t, http.StatusOK, send(WithRequestTransformer(func(req *http.Request) error {
+		req.Header.Set(HeaderAuthorization, "bearer token-1")
+		return nil
+	})))
+
+	// Rotate the token
+	receiverKeys.Rotate([]byte("token-2"), []byte("token-1"))
+	requireStatus(t, http.StatusOK, send(WithRequestTransformer(BearerToken(senderKeys))))
+	senderKeys.Rotate([]byte("token-2"))
+	requireStatus(t, http.StatusOK, send(WithRequestTransformer(BearerToken(senderKeys))))
+	receiverKeys.Rotate([]byte("token-2"))
+	requireStatus(t, http.StatusForbidden, send(WithRequestTransformer(BearerToken(NewKeyRing([]byte("token-1"))))))
+	requireStatus(t, http.StatusOK, send(WithRequestTransformer(BearerToken(senderKeys))))
+
+	cancel()
+}
+
+func TestHMACSignature(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiverKeys := NewKeyRing([]byte("key-1"))
+	target := openAuthReceiver(t, ctx, HMACSignatureAuth(receiverKeys, 0, 0))
+	send := func(opts ...Option) error {
+		sender, err := New(append(opts, WithTarget(target))...)
+		require.NoError(t, err)
+		return sender.Send(ctx, test.MinMessage())
+	}
+
+	senderKeys := NewKeyRing([]byte("key-1"))
+	requireStatus(t, http.StatusOK, send(WithRequestTransformer(HMACSignature(senderKeys))))
+	// The same event can be sent twice
+	requireStatus(t, http.StatusOK, send(WithRequestTransformer(HMACSignature(senderKeys))))
+	requireStatus(t, http.StatusUnauthorized, send())
+	requireStatus(t, http.StatusForbidden, send(WithRequestTransformer(HMACSignature(NewKeyRing([]byte("key-2"))))))
+
+	t.Run("tampered attribute", func(t *testing.T) {
+		requireStatus(t, http.StatusForbidden, send(
+			WithRequestTransformer(HMACSignature(senderKeys)),
+			WithRequestTransformer(func(req *http.Request) error {
+				req.Header.Set("Ce-Type", "tampered")
+				return nil
+			}),
+		))
+	})
+
+	t.Run("rotation", func(t *testing.T) {
+		receiverKeys.Rotate([]byte("key-2"), []byte("key-1"))
+		requireStatus(t, http.StatusOK, send(WithRequestTransformer(HMACSignature(senderKeys))))
+		senderKeys.Rotate([]byte("key-2"))
+		requireStatus(t, http.StatusOK, send(WithRequestTransformer(HMACSignature(senderKeys))))
+		receiverKeys.Rotate([]byte("key-2"))
+		requireStatus(t, http.StatusForbidden, send(WithRequestTransformer(HMACSignature(NewKeyRing([]byte("key-1"))))))
+	})
+
+	cancel()
+}
+
+func TestHMACSignatureReplay(t *testing.T) {
+	keys := NewKeyRing([]byte("key"))
+	handler := HMACSignatureAuth(keys, time.Minute, 0)(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	serve := func(req *http.Request) int {
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw.Code
+	}
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"hello":"world"}`))
+		req.Header.Set("Ce-Id", "1")
+		return req
+	}
+
+	req := newRequest()
+	require.NoError(t, HMACSignature(keys)(req))
+	signature := req.Header.Get(HeaderWebhookSignature)
+	require.Equal(t, http.StatusOK, serve(req))
+
+	replayed := newRequest()
+	replayed.Header.Set(HeaderWebhookSignature, signature)
+	require.Equal(t, http.StatusForbidden, serve(replayed))
+
+	expired := newRequest()
+	timestamp := strconv.FormatInt(time.Now().Add(-2*time.Minute).Unix(), 10)
+	expired.Header.Set(HeaderWebhookSignature, "t="+timestamp+",n=expired,v1="+
+		strings.Repeat("0", 64))
+	require.Equal(t, http.StatusForbidden, serve(expired))
+
+	malformed := newRequest()
+	malformed.Header.Set(HeaderWebhookSignature, "v1=00")
+	require.Equal(t, http.StatusForbidden, serve(malformed))
+}
+
+func TestHMACSignatureMaxBodySize(t *testing.T) {
+	keys := NewKeyRing([]byte("key"))
+	handler := HMACSignatureAuth(keys, 0, 8)(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {}))
+	serve := func(body string, contentLength int64) int {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		require.NoError(t, HMACSignature(keys)(req))
+		req.ContentLength = contentLength
+		rw := httptest.NewRecorder()
+		handler.ServeHTTP(rw, req)
+		return rw.Code
+	}
+
+	require.Equal(t, http.StatusOK, serve("12345678", 8))
+	require.Equal(t, http.StatusRequestEntityTooLarge, serve("123456789", 9))
+	// Without Content-Length, the body is read up to the limit
+	require.Equal(t, http.StatusOK, serve("12345678", -1))
+	require.Equal(t, http.StatusRequestEntityTooLarge, serve("123456789", -1))
+}
+
+func TestWithRequestTransformer(t *testing.T) {
+	_, err := New(WithRequestTransformer(nil))
+	require.Error(t, err)
+
+	p, err := New(WithTarget("http://localhost"), WithRequestTransformer(BearerToken(NewKeyRing())))
+	require.NoError(t, err)
+	err = p.Send(context.Background(), test.MinMessage())
+	require.ErrorContains(t, err, "no key in key ring")
+}
@@ -0,0 +1,175 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// ContentEncodingGzip is the gzip Content-Encoding.
+	ContentEncodingGzip = "gzip"
+	// ContentEncodingZstd is the zstd Content-Encoding.
+	ContentEncodingZstd = "zstd"
+)
+
+// ErrUnsupportedContentEncoding is returned when reading a body compressed with an unknown Content-Encoding.
+var ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+
+type compressionKey struct{}
+
+// compression is the configuration of the compression of the bodies.
+type compression struct {
+	encoding  string
+	threshold int64
+}
+
+// WithCompressionAtContext returns a context making WriteRequest, WriteBatchRequest and
+// WriteResponseWriter compress the bodies of at least threshold bytes with encoding,
+// ContentEncodingGzip or ContentEncodingZstd.
+// The bodies of unknown length, e.g. piped from another HTTP message, are always compressed.
+func WithCompressionAtContext(ctx context.Context, encoding string, threshold int) context.Context {
+	return context.WithValue(ctx, compressionKey{}, &compression{encoding: encoding, threshold: int64(threshold)})
+}
+
+func compressionFrom(ctx context.Context) *compression {
+	c, _ := ctx.Value(compressionKey{}).(*compression)
+	return c
+}
+
+func validateContentEncoding(encoding string) error {
+	switch encoding {
+	case ContentEncodingGzip, ContentEncodingZstd:
+		return nil
+	}
+	return fmt.Errorf("%w %q", ErrUnsupportedContentEncoding, encoding)
+}
+
+// compress returns body compressed with c, unless its size is known and smaller than the
+// threshold. The body is compressed in memory if it's buffered, otherwise while it's read.
+func (c *compression) compress(body io.Reader, size int64, buffered bool) (io.Reader, bool, error) {
+	if size >= 0 && size < c.threshold {
+		return body, false, nil
+	}
+	if err := validateContentEncoding(c.encoding); err != nil {
+		return nil, false, err
+	}
+	if buffered {
+		var buf bytes.Buffer
+		if err := c.copy(&buf, body); err != nil {
+			return nil, false, err
+		}
+		return &buf, true, nil
+	}
+	r, w := io.Pipe()
+	go func() {
+		_ = w.CloseWithError(c.copy(w, body))
+	}()
+	return r, true, nil
+}
+
+// copy writes body compressed with c to w.
+func (c *compression) copy(w io.Writer, body io.Reader) error {
+	var cw io.WriteCloser
+	switch c.encoding {
+	case ContentEncodingGzip:
+		cw = gzip.NewWriter(w)
+	case ContentEncodingZstd:
+		zw, err := zstd.NewWriter(w)
+		if err != nil {
+			return err
+		}
+		cw = zw
+	}
+	if _, err := io.Copy(cw, body); err != nil {
+		_ = cw.Close()
+		return err
+	}
+	return cw.Close()
+}
+
+// isContentEncoded returns whether the body is compressed according to header.
+func isContentEncoded(header http.Header) bool {
+	encoding := header.Get(ContentEncoding)
+	return encoding != "" && !strings.EqualFold(encoding, "identity")
+}
+
+// decodeBody returns body decompressed according to the Content-Encoding of header.
+// The decompression starts at the first read, so the errors are returned by the reader.
+func decodeBody(header http.Header, body io.ReadCloser) io.ReadCloser {
+	if body == nil || body == http.NoBody || !isContentEncoded(header) {
+		return body
+	}
+	return &decodingBody{encoding: strings.ToLower(header.Get(ContentEncoding)), body: body}
+}
+
+// decodingBody decompresses the body with encoding.
+type decodingBody struct {
+	encoding string
+	body     io.ReadCloser
+	reader   io.Reader
+	err      error
+	close    func()
+}
+
+func (d *decodingBody) Read(p []byte) (int, error) {
+	if d.reader == nil && d.err == nil {
+		d.err = d.open()
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.reader.Read(p)
+}
+
+func (d *decodingBody) open() error {
+	switch d.encoding {
+	case ContentEncodingGzip, "x-gzip":
+		r, err := gzip.NewReader(d.body)
+		if err != nil {
+			return fmt.Errorf("reading gzip body: %w", err)
+		}
+		d.reader = r
+	case ContentEncodingZstd:
+		r, err := zstd.NewReader(d.body)
+		if err != nil {
+			return fmt.Errorf("reading zstd body: %w", err)
+		}
+		d.reader, d.close = r, r.Close
+	default:
+		return fmt.Errorf("%w %q", ErrUnsupportedContentEncoding, d.encoding)
+	}
+	return nil
+}
+
+func (d *decodingBody) Close() error {
+	if d.close != nil {
+		d.close()
+	}
+	return d.body.Close()
+}
+
+// acceptsEncoding returns whether the Accept-Encoding of header accepts encoding.
+func acceptsEncoding(header http.Header, encoding string) bool {
+	for _, value := range header.Values("Accept-Encoding") {
+		for _, accepted := range strings.Split(value, ",") {
+			name, params, _ := strings.Cut(strings.TrimSpace(accepted), ";")
+			if strings.EqualFold(strings.TrimSpace(name), encoding) || strings.TrimSpace(name) == "*" {
+				return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+			}
+		}
+	}
+	return false
+}
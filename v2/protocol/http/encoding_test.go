@@ -0,0 +1,202 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	bindingtest "github.com/cloudevents/sdk-go/v2/binding/test"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+// compressionTestEvent returns test.MinEvent with data large enough to be worth compressing.
+func compressionTestEvent(t *testing.T) event.Event {
+	e := test.MinEvent()
+	require.NoError(t, e.SetData(event.TextPlain, strings.Repeat("a", 1024)))
+	return e
+}
+
+func TestCompression(t *testing.T) {
+	for _, encoding := range []string{ContentEncodingGzip, ContentEncodingZstd} {
+		t.Run(encoding, func(t *testing.T) {
+			ctx := WithCompressionAtContext(context.Background(), encoding, 0)
+
+			t.Run("request", func(t *testing.T) {
+				bindingtest.RunRoundTripTests(t, ctx, func(t *testing.T, ctx context.Context, m binding.Message) binding.Message {
+					req := httptest.NewRequest(http.MethodPost, "http://localhost", nil)
+					require.NoError(t, WriteRequest(ctx, m, req))
+					if req.Body != nil && req.Body != http.NoBody {
+						require.Equal(t, encoding, req.Header.Get(ContentEncoding))
+					}
+					return NewMessageFromHttpRequest(req)
+				})
+			})
+
+			t.Run("response", func(t *testing.T) {
+				bindingtest.RunRoundTripTests(t, ctx, func(t *testing.T, ctx context.Context, m binding.Message) binding.Message {
+					rw := httptest.NewRecorder()
+					require.NoError(t, WriteResponseWriter(ctx, m, http.StatusOK, rw))
+					resp := rw.Result()
+					if rw.Body.Len() > 0 {
+						require.Equal(t, encoding, resp.Header.Get(ContentEncoding))
+					}
+					return NewMessageFromHttpResponse(resp)
+				})
+			})
+		})
+	}
+
+	t.Run("threshold", func(t *testing.T) {
+		ctx := WithCompressionAtContext(context.Background(), ContentEncodingGzip, 1024)
+		bindingtest.RunRoundTripTests(t, ctx, func(t *testing.T, ctx context.Context, m binding.Message) binding.Message {
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", nil)
+			require.NoError(t, WriteRequest(ctx, m, req))
+			require.Empty(t, req.Header.Get(ContentEncoding))
+			return NewMessageFromHttpRequest(req)
+		})
+	})
+
+	t.Run("streamed body", func(t *testing.T) {
+		ctx := WithCompressionAtContext(context.Background(), ContentEncodingZstd, 1<<20)
+		data := strings.Repeat("a", 1024)
+		header := http.Header{}
+		header.Set("Ce-Specversion", "1.0")
+		header.Set("Ce-Id", "1")
+		header.Set("Ce-Type", "unit.test")
+		header.Set("Ce-Source", "/unit/test")
+
+		// The length of the body is unknown, so it's compressed regardless of the threshold
+		req := httptest.NewRequest(http.MethodPost, "http://localhost", nil)
+		require.NoError(t, WriteRequest(ctx, NewMessage(header, io.NopCloser(strings.NewReader(data))), req))
+		require.Equal(t, ContentEncodingZstd, req.Header.Get(ContentEncoding))
+		e, err := binding.ToEvent(ctx, NewMessageFromHttpRequest(req))
+		require.NoError(t, err)
+		require.Equal(t, data, string(e.Data()))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		ctx := WithCompressionAtContext(context.Background(), "br", 0)
+		e := compressionTestEvent(t)
+		req := httptest.NewRequest(http.MethodPost, "http://localhost", nil)
+		require.ErrorIs(t, WriteRequest(ctx, binding.ToMessage(&e), req), ErrUnsupportedContentEncoding)
+
+		req = httptest.NewRequest(http.MethodPost, "http://localhost", strings.NewReader("data"))
+		req.Header.Set(ContentType, event.ApplicationCloudEventsJSON)
+		req.Header.Set(ContentEncoding, "br")
+		_, err := binding.ToEvent(ctx, NewMessageFromHttpRequest(req))
+		require.ErrorIs(t, err, ErrUnsupportedContentEncoding)
+	})
+}
+
+func TestWithCompression(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, err := New(WithCompression("br", 0))
+	require.ErrorIs(t, err, ErrUnsupportedContentEncoding)
+	_, err = New(WithCompression(ContentEncodingGzip, -1))
+	require.Error(t, err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver, err := New(WithListener(listener), WithMaxBodySize(64*1024))
+	require.NoError(t, err)
+	received := make(chan string, 1)
+	go func() {
+		_ = receiver.OpenInbound(ctx)
+	}()
+	go func() {
+		for {
+			m, fn, err := receiver.Respond(ctx)
+			if err != nil {
+				return
+			}
+			e, err := binding.ToEvent(ctx, m)
+			_ = m.Finish(nil)
+			if err != nil {
+				_ = fn(ctx, nil, protocol.NewReceipt(false, "%w", err))
+				continue
+			}
+			received <- string(e.Data())
+			_ = fn(ctx, nil, protocol.ResultACK)
+		}
+	}()
+	target := "http://" + listener.Addr().String()
+
+	sender, err := New(WithTarget(target), WithCompression(ContentEncodingZstd, 0))
+	require.NoError(t, err)
+	e := compressionTestEvent(t)
+	requireStatus(t, http.StatusOK, sender.Send(ctx, binding.ToMessage(&e)))
+	require.Equal(t, strings.Repeat("a", 1024), <-received)
+
+	t.Run("decompressed body too large", func(t *testing.T) {
+		var body bytes.Buffer
+		gw := gzip.NewWriter(&body)
+		_, err := gw.Write(make([]byte, 128*1024))
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+
+		req, err := http.NewRequest(http.MethodPost, target, &body)
+		require.NoError(t, err)
+		req.Header.Set("Ce-Specversion", "1.0")
+		req.Header.Set("Ce-Id", "1")
+		req.Header.Set("Ce-Type", "unit.test")
+		req.Header.Set("Ce-Source", "/unit/test")
+		req.Header.Set(ContentEncoding, ContentEncodingGzip)
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+	})
+}
+
+func TestServeHTTP_ResponseCompression(t *testing.T) {
+	ctx := context.Background()
+	p, err := New(WithCompression(ContentEncodingGzip, 0))
+	require.NoError(t, err)
+
+	for acceptEncoding, want := range map[string]string{
+		"":                  "",
+		"gzip":              ContentEncodingGzip,
+		"br, gzip;q=0.5":    ContentEncodingGzip,
+		"zstd, gzip;q=0":    "",
+		"*":                 ContentEncodingGzip,
+		"identity, deflate": "",
+	} {
+		t.Run(acceptEncoding, func(t *testing.T) {
+			e := compressionTestEvent(t)
+			req := httptest.NewRequest(http.MethodPost, "http://localhost", nil)
+			require.NoError(t, WriteRequest(ctx, binding.ToMessage(&e), req))
+			req.Header.Set("Accept-Encoding", acceptEncoding)
+			rw := httptest.NewRecorder()
+			go p.ServeHTTP(rw, req)
+
+			m, fn, err := p.Respond(ctx)
+			require.NoError(t, err)
+			require.NoError(t, m.Finish(nil))
+			resp := compressionTestEvent(t)
+			require.NoError(t, fn(ctx, binding.ToMessage(&resp), protocol.ResultACK))
+			require.Equal(t, want, rw.Header().Get(ContentEncoding))
+
+			got, err := binding.ToEvent(ctx, NewMessageFromHttpResponse(rw.Result()))
+			require.NoError(t, err)
+			require.Equal(t, resp.Data(), got.Data())
+		})
+	}
+}
@@ -33,6 +33,7 @@ var specs = spec.WithPrefixMatchExact(
 
 const ContentType = "Content-Type"
 const ContentLength = "Content-Length"
+const ContentEncoding = "Content-Encoding"
 
 // Message holds the Header and Body of a HTTP Request or Response.
 // The Message instance *must* be constructed from NewMessage function.
@@ -43,6 +44,8 @@ type Message struct {
 	OnFinish   func(error) error
 
 	ctx context.Context
+	// contentLength is the length of the body, or -1 if unknown
+	contentLength int64
 
 	format  format.Format
 	version spec.Version
@@ -56,9 +59,9 @@ var _ binding.MessageMetadataReader = (*Message)(nil)
 // NewMessage returns a binding.Message with header and data.
 // The returned binding.Message *cannot* be read several times. In order to read it more times, buffer it using binding/buffering methods
 func NewMessage(header nethttp.Header, body io.ReadCloser) *Message {
-	m := Message{Header: header}
+	m := Message{Header: header, contentLength: -1}
 	if body != nil {
-		m.BodyReader = body
+		m.BodyReader = decodeBody(header, body)
 	}
 	if m.format = format.Lookup(header.Get(ContentType)); m.format == nil {
 		m.version = specs.Version(m.Header.Get(specs.PrefixedSpecVersionName()))
@@ -74,6 +77,9 @@ func NewMessageFromHttpRequest(req *nethttp.Request) *Message {
 	}
 	message := NewMessage(req.Header, req.Body)
 	message.ctx = req.Context()
+	if !isContentEncoded(req.Header) {
+		message.contentLength = req.ContentLength
+	}
 	return message
 }
 
@@ -84,6 +90,9 @@ func NewMessageFromHttpResponse(resp *nethttp.Response) *Message {
 		return nil
 	}
 	msg := NewMessage(resp.Header, resp.Body)
+	if !isContentEncoded(resp.Header) {
+		msg.contentLength = resp.ContentLength
+	}
 	return msg
 }
 
@@ -92,7 +101,7 @@ func (m *Message) ReadEncoding() binding.Encoding {
 		return binding.EncodingBinary
 	}
 	if m.format != nil {
-		if m.format == format.JSONBatch {
+		if _, ok := m.format.(format.BatchFormat); ok {
 			return binding.EncodingBatch
 		}
 		return binding.EncodingStructured
@@ -100,11 +109,17 @@ func (m *Message) ReadEncoding() binding.Encoding {
 	return binding.EncodingUnknown
 }
 
+// BatchFormat returns the format of a message in batch mode, or nil.
+func (m *Message) BatchFormat() format.BatchFormat {
+	f, _ := m.format.(format.BatchFormat)
+	return f
+}
+
 func (m *Message) ReadStructured(ctx context.Context, encoder binding.StructuredWriter) error {
 	if m.format == nil {
 		return binding.ErrNotStructured
 	} else {
-		return encoder.SetStructuredEvent(ctx, m.format, m.BodyReader)
+		return encoder.SetStructuredEvent(ctx, m.format, m.body())
 	}
 }
 
@@ -131,7 +146,7 @@ func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter)
 	}
 
 	if m.BodyReader != nil {
-		err = encoder.SetData(m.BodyReader)
+		err = encoder.SetData(m.body())
 		if err != nil {
 			return err
 		}
@@ -140,6 +155,21 @@ func (m *Message) ReadBinary(ctx context.Context, encoder binding.BinaryWriter)
 	return
 }
 
+// body returns the BodyReader, carrying its length when known, so that it can be
+// written to another HTTP request or response with a Content-Length.
+func (m *Message) body() io.Reader {
+	if m.contentLength >= 0 && m.BodyReader != nil {
+		return &sizedBody{ReadCloser: m.BodyReader, size: m.contentLength}
+	}
+	return m.BodyReader
+}
+
+// sizedBody is a body of known length.
+type sizedBody struct {
+	io.ReadCloser
+	size int64
+}
+
 func (m *Message) GetAttribute(k spec.Kind) (spec.Attribute, interface{}) {
 	attr := m.version.AttributeFromKind(k)
 	if attr != nil {
@@ -6,6 +6,8 @@
 package http
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net"
 	nethttp "net/http"
@@ -256,6 +258,146 @@ func WithRoundTripperDecorator(decorator func(roundTripper nethttp.RoundTripper)
 	}
 }
 
+// WithRequestTransformer adds a RequestTransformer modifying the outbound requests,
+// e.g. BearerToken or HMACSignature to authenticate them. It may be specified multiple
+// times, the transformers are applied in order.
+func WithRequestTransformer(transformer RequestTransformer) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http request transformer option can not set nil protocol")
+		}
+		if transformer == nil {
+			return fmt.Errorf("http request transformer option was given a nil transformer")
+		}
+		p.transformers = append(p.transformers, transformer)
+		return nil
+	}
+}
+
+// WithH2C makes the server accept unencrypted HTTP/2 connections with prior knowledge (h2c),
+// alongside HTTP/1 ones. The optional config tunes the HTTP/2 server.
+func WithH2C(config *nethttp.HTTP2Config) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http h2c option can not set nil protocol")
+		}
+		p.serverProtocols = new(nethttp.Protocols)
+		p.serverProtocols.SetHTTP1(true)
+		p.serverProtocols.SetHTTP2(true)
+		p.serverProtocols.SetUnencryptedHTTP2(true)
+		p.serverHTTP2Config = config
+		return nil
+	}
+}
+
+// WithH2CTransport makes the client send the requests using HTTP/2, without TLS (h2c) for http targets.
+// The targets must accept unencrypted HTTP/2 connections with prior knowledge.
+// The optional config tunes the HTTP/2 transport.
+func WithH2CTransport(config *nethttp.HTTP2Config) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http h2c transport option can not set nil protocol")
+		}
+		transport := nethttp.DefaultTransport.(*nethttp.Transport).Clone()
+		transport.Protocols = new(nethttp.Protocols)
+		transport.Protocols.SetHTTP2(true)
+		transport.Protocols.SetUnencryptedHTTP2(true)
+		transport.HTTP2 = config
+		p.roundTripper = transport
+		return nil
+	}
+}
+
+// WithTLSConfig makes the server accept only TLS connections, using the provided config.
+// The config must provide the server certificates, unless WithCertificateFiles is used.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http TLS config option can not set nil protocol")
+		}
+		if config == nil {
+			return fmt.Errorf("http TLS config can not be nil")
+		}
+		p.tlsConfig = config.Clone()
+		return nil
+	}
+}
+
+// WithCertificateFiles makes the server accept only TLS connections, authenticating with the
+// PEM encoded certificate and key read from the provided files.
+// The files are reloaded when they are modified, so certificates can be rotated without restarting the server.
+func WithCertificateFiles(certFile, keyFile string) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http certificate files option can not set nil protocol")
+		}
+		certificates, err := newCertificateReloader(certFile, keyFile)
+		if err != nil {
+			return fmt.Errorf("http certificate files option: %w", err)
+		}
+		p.certificates = certificates
+		return nil
+	}
+}
+
+// WithClientCAs makes the server require TLS clients to authenticate with a certificate
+// signed by one of the provided CAs (mutual TLS).
+// The verified client certificate can be retrieved from the receiver context with PeerCertificateFromContext.
+// It must be used along with WithTLSConfig or WithCertificateFiles providing the server certificate,
+// otherwise New fails.
+func WithClientCAs(pool *x509.CertPool) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http client CAs option can not set nil protocol")
+		}
+		if pool == nil {
+			return fmt.Errorf("http client CAs can not be nil")
+		}
+		p.clientCAs = pool
+		return nil
+	}
+}
+
+// WithTLSClientConfig sets the TLS config used to send the requests, e.g. to trust private CAs
+// or to authenticate with a client certificate.
+func WithTLSClientConfig(config *tls.Config) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http TLS client config option can not set nil protocol")
+		}
+		if config == nil {
+			return fmt.Errorf("http TLS client config can not be nil")
+		}
+		transport, ok := p.roundTripper.(*nethttp.Transport)
+		if ok {
+			transport = transport.Clone()
+		} else if p.roundTripper == nil {
+			transport = nethttp.DefaultTransport.(*nethttp.Transport).Clone()
+		} else {
+			return fmt.Errorf("http TLS client config can not be set on a custom round tripper")
+		}
+		transport.TLSClientConfig = config.Clone()
+		p.roundTripper = transport
+		return nil
+	}
+}
+
+// WithInboundServer sets the server serving the Protocol handler in place of the default http.Server,
+// e.g. to receive events over HTTP/3. When set, the port and the listener options are ignored,
+// as well as the read and write timeouts and the TLS options.
+func WithInboundServer(server InboundServer) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http inbound server option can not set nil protocol")
+		}
+		if server == nil {
+			return fmt.Errorf("http inbound server can not be nil")
+		}
+		p.inboundServer = server
+		return nil
+	}
+}
+
 // WithClient sets the protocol client
 func WithClient(client nethttp.Client) Option {
 	return func(p *Protocol) error {
@@ -291,7 +433,8 @@ func WithOptionsHandlerFunc(fn nethttp.HandlerFunc) Option {
 
 // WithDefaultOptionsHandlerFunc sets the options handler to be the built in handler and configures the options.
 // methods: the supported methods reported to OPTIONS caller.
-// rate: the rate limit reported to OPTIONS caller.
+// rate: the rate limit reported to OPTIONS caller, in requests per minute. If 0, the rate
+// of the RateLimiter set with WithRateLimiter is reported, if it is an AllowedRateLimiter.
 // origins: the prefix of the accepted origins, or "*".
 // callback: preform the callback to ACK the OPTIONS request.
 func WithDefaultOptionsHandlerFunc(methods []string, rate int, origins []string, callback bool) Option {
@@ -300,9 +443,13 @@ func WithDefaultOptionsHandlerFunc(methods []string, rate int, origins []string,
 			return fmt.Errorf("http OPTIONS handler func can not set nil protocol")
 		}
 		p.OptionsHandlerFn = p.OptionsHandler
+		var allowedRate *int
+		if rate > 0 {
+			allowedRate = &rate
+		}
 		p.WebhookConfig = &WebhookConfig{
 			AllowedMethods:  methods,
-			AllowedRate:     &rate,
+			AllowedRate:     allowedRate,
 			AllowedOrigins:  origins,
 			AutoACKCallback: callback,
 		}
@@ -310,6 +457,24 @@ func WithDefaultOptionsHandlerFunc(methods []string, rate int, origins []string,
 	}
 }
 
+// WithWebhookHandshake makes the sender run the validation handshake of the CloudEvents
+// Webhook spec with a delivery target before the first delivery to it, and send the
+// following requests at the rate it allows.
+// If the delivery target doesn't grant the permission, sending fails and the handshake
+// is run again on the next attempt.
+func WithWebhookHandshake(config WebhookHandshakeConfig) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http webhook handshake option can not set nil protocol")
+		}
+		if config.Origin == "" {
+			return fmt.Errorf("http webhook handshake option requires an origin")
+		}
+		p.webhookHandshakeConfig = &config
+		return nil
+	}
+}
+
 // IsRetriable is a custom function that can be used to override the
 // default retriable status codes.
 type IsRetriable func(statusCode int) bool
@@ -329,6 +494,8 @@ func WithIsRetriableFunc(isRetriable IsRetriable) Option {
 	}
 }
 
+// WithRateLimiter sets the RateLimiter applied to the inbound requests, e.g. NewClientIPRateLimiter.
+// The requests it rejects are answered with 429 Too Many Requests and a Retry-After header.
 func WithRateLimiter(rl RateLimiter) Option {
 	return func(p *Protocol) error {
 		if p == nil {
@@ -339,6 +506,60 @@ func WithRateLimiter(rl RateLimiter) Option {
 	}
 }
 
+// WithBatchAckMode sets how the results of the events received in a batch are
+// aggregated in the HTTP response. If not set, BatchAckAllOrNothing is used.
+func WithBatchAckMode(mode BatchAckMode) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http batch ack mode option can not set nil protocol")
+		}
+		switch mode {
+		case BatchAckAllOrNothing, BatchAckPartial:
+			p.batchAckMode = mode
+			return nil
+		}
+		return fmt.Errorf("unknown http batch ack mode %q", mode)
+	}
+}
+
+// WithMaxBodySize limits the size of the body of the inbound requests to size bytes.
+// The requests announcing a larger Content-Length are rejected with 413 Request Entity Too Large
+// before being received, while reading beyond size the body of the other requests fails with an
+// *http.MaxBytesError, which is answered with 413 as well.
+func WithMaxBodySize(size int64) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http max body size option can not set nil protocol")
+		}
+		if size <= 0 {
+			return fmt.Errorf("http max body size must be positive, got %d", size)
+		}
+		p.maxBodySize = size
+		return nil
+	}
+}
+
+// WithCompression compresses the bodies of at least threshold bytes with encoding,
+// ContentEncodingGzip or ContentEncodingZstd, in both binary and structured modes:
+// the bodies of the sent requests, and the bodies of the responses to the requests
+// accepting encoding. The compressed bodies are decompressed on receive regardless of
+// this option.
+func WithCompression(encoding string, threshold int) Option {
+	return func(p *Protocol) error {
+		if p == nil {
+			return fmt.Errorf("http compression option can not set nil protocol")
+		}
+		if err := validateContentEncoding(encoding); err != nil {
+			return err
+		}
+		if threshold < 0 {
+			return fmt.Errorf("http compression threshold must not be negative, got %d", threshold)
+		}
+		p.compression = &compression{encoding: encoding, threshold: int64(threshold)}
+		return nil
+	}
+}
+
 // WithRequestDataAtContextMiddleware adds to the Context RequestData.
 // This enables a user's dispatch handler to inspect HTTP request information by
 // retrieving it from the Context.
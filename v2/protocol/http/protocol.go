@@ -8,6 +8,8 @@ package http
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"io"
@@ -30,7 +32,7 @@ const (
 )
 
 type msgErr struct {
-	msg    *Message
+	msg    binding.Message
 	respFn protocol.ResponseFn
 	err    error
 }
@@ -90,16 +92,36 @@ type Protocol struct {
 
 	// Receive Mutex
 	reMu sync.Mutex
+	// stopInbound stops the running OpenInbound, guarded by stopMu.
+	stopMu      sync.Mutex
+	stopInbound context.CancelFunc
 	// Handler is the handler the http Server will use. Use this to reuse the
 	// http server. If nil, the Protocol will create a one.
 	Handler *http.ServeMux
 
 	listener          atomic.Value
 	roundTripper      http.RoundTripper
-	server            *http.Server
+	server            InboundServer
+	inboundServer     InboundServer
+	serverProtocols   *http.Protocols
+	serverHTTP2Config *http.HTTP2Config
+	tlsConfig         *tls.Config
+	certificates      *certificateReloader
+	clientCAs         *x509.CertPool
 	handlerRegistered bool
+	routed            bool
 	middleware        []Middleware
+	transformers      []RequestTransformer
 	limiter           RateLimiter
+	batchAckMode      BatchAckMode
+	maxBodySize       int64
+	compression       *compression
+
+	// Validation handshakes with the delivery targets, when enabled by WithWebhookHandshake
+	webhookHandshakeConfig *WebhookHandshakeConfig
+	webhookMu              sync.Mutex
+	webhookTargets         map[string]*webhookTarget
+	webhookCallbacks       map[string]*webhookTarget
 
 	isRetriableFunc IsRetriable
 }
@@ -112,6 +134,9 @@ func New(opts ...Option) (*Protocol, error) {
 	if err := p.applyOptions(opts...); err != nil {
 		return nil, err
 	}
+	if err := p.validateServerTLS(); err != nil {
+		return nil, err
+	}
 
 	if p.Client == nil {
 		// This is how http.DefaultClient is initialized. We do not just use
@@ -176,21 +201,60 @@ func (p *Protocol) Send(ctx context.Context, m binding.Message, transformers ...
 	if msg != nil {
 		defer func() { _ = msg.Finish(err) }()
 	}
-	if err != nil && !protocol.IsACK(err) {
-		var res *Result
-		if protocol.ResultAs(err, &res) {
-			if message, ok := msg.(*Message); ok {
-				buf := new(bytes.Buffer)
-				buf.ReadFrom(message.BodyReader)
-				errorStr := buf.String()
-				// If the error is not wrapped, then append the original error string.
-				if og, ok := err.(*Result); ok {
-					og.Format = og.Format + "%s"
-					og.Args = append(og.Args, errorStr)
-					err = og
-				} else {
-					err = NewResult(res.StatusCode, "%w: %s", err, errorStr)
-				}
+	err = withResponseBody(msg, err)
+	return err
+}
+
+// SendBatch implements protocol.BatchSender, sending the events in a single request
+// using the "application/cloudevents-batch+json" format.
+// When the receiver answers with 207 Multi-Status, as with BatchAckPartial, the result is
+// a *protocol.BatchResult holding the result of each event.
+func (p *Protocol) SendBatch(ctx context.Context, events []event.Event) error {
+	if ctx == nil {
+		return fmt.Errorf("nil Context")
+	} else if len(events) == 0 {
+		return fmt.Errorf("empty batch")
+	}
+
+	req := p.makeRequest(ctx)
+
+	if p.Client == nil || req == nil || req.URL == nil {
+		return fmt.Errorf("not initialized: %#v", p)
+	}
+
+	if err := WriteBatchRequest(p.compressionContext(ctx), events, req); err != nil {
+		return err
+	}
+
+	msg, err := p.do(ctx, req)
+	if msg != nil {
+		defer func() { _ = msg.Finish(err) }()
+	}
+	err = withResponseBody(msg, err)
+	err = withItemResults(events, msg, err)
+	return err
+}
+
+var _ protocol.BatchSender = (*Protocol)(nil)
+
+// withResponseBody appends the body of the response msg to a non ACK err.
+func withResponseBody(msg binding.Message, err error) error {
+	if err == nil || protocol.IsACK(err) {
+		return err
+	}
+	var res *Result
+	if protocol.ResultAs(err, &res) {
+		if message, ok := msg.(*Message); ok {
+			buf := new(bytes.Buffer)
+			buf.ReadFrom(message.BodyReader)
+			errorStr := buf.String()
+			// If the error is not wrapped, then append the original error string.
+			if og, ok := err.(*Result); ok {
+				og.Format = og.Format + "%s"
+				og.Args = append(og.Args, errorStr)
+				err = og
+			} else {
+				err = NewResult(res.StatusCode, "%w: %s", err, errorStr)
 			}
 		}
 	}
@@ -214,13 +278,22 @@ func (p *Protocol) Request(ctx context.Context, m binding.Message, transformers
 		return nil, fmt.Errorf("not initialized: %#v", p)
 	}
 
-	if err = WriteRequest(ctx, m, req, transformers...); err != nil {
+	if err = WriteRequest(p.compressionContext(ctx), m, req, transformers...); err != nil {
 		return nil, err
 	}
 
 	return p.do(ctx, req)
 }
 
+// compressionContext returns ctx compressing the bodies as configured by WithCompression,
+// unless ctx already configures the compression with WithCompressionAtContext.
+func (p *Protocol) compressionContext(ctx context.Context) context.Context {
+	if p.compression == nil || compressionFrom(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, compressionKey{}, p.compression)
+}
+
 func (p *Protocol) makeRequest(ctx context.Context) *http.Request {
 	req := &http.Request{
 		Method: http.MethodPost,
@@ -316,6 +389,11 @@ func (p *Protocol) Respond(ctx context.Context) (binding.Message, protocol.Respo
 // ServeHTTP implements http.Handler.
 // Blocks until ResponseFn is invoked.
 func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	// The rate limiters reading the body read it up to the same size
+	if p.maxBodySize > 0 {
+		req = req.WithContext(context.WithValue(req.Context(), maxBodySizeKey{}, p.maxBodySize))
+	}
+
 	// always apply limiter first using req context
 	ok, reset, err := p.limiter.Allow(req.Context(), req)
 	if err != nil {
@@ -357,6 +435,31 @@ func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if p.maxBodySize > 0 {
+		if req.ContentLength > p.maxBodySize {
+			http.Error(rw, fmt.Sprintf("body larger than %d bytes", p.maxBodySize), http.StatusRequestEntityTooLarge)
+			return
+		}
+		req.Body = http.MaxBytesReader(rw, req.Body, p.maxBodySize)
+		// The limit applies to the decompressed body as well
+		if isContentEncoded(req.Header) {
+			req.Body = http.MaxBytesReader(rw, decodeBody(req.Header, req.Body), p.maxBodySize)
+			req.Header = req.Header.Clone()
+			req.Header.Del(ContentEncoding)
+			req.ContentLength = -1
+		}
+	}
+
+	// Expose the verified client certificate to the receiver
+	if req.TLS != nil {
+		req = req.WithContext(withPeerCertificate(req.Context(), req.TLS))
+	}
+
+	if IsHTTPBatch(req.Header) {
+		p.serveBatch(rw, req)
+		return
+	}
+
 	m := NewMessageFromHttpRequest(req)
 	if m == nil {
 		// Should never get here unless ServeHTTP is called directly.
@@ -403,15 +506,15 @@ func (p *Protocol) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 					rw.WriteHeader(status)
 					_, _ = rw.Write([]byte(validationError.Error()))
 					return validationError
-				} else if errors.Is(res, binding.ErrUnknownEncoding) {
-					status = http.StatusUnsupportedMediaType
-				} else {
-					status = http.StatusInternalServerError
 				}
+				status = nackStatus(res)
 			}
 		}
 
 		if respMsg != nil {
+			if p.compression != nil && acceptsEncoding(req.Header, p.compression.encoding) {
+				ctx = p.compressionContext(ctx)
+			}
 			err := WriteResponseWriter(ctx, respMsg, status, rw, transformers...)
 			return respMsg.Finish(err)
 		}
@@ -0,0 +1,211 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// BatchAckMode defines how the results of the events received in a
+// "application/cloudevents-batch+json" request are aggregated in the response.
+type BatchAckMode string
+
+const (
+	// BatchAckAllOrNothing acknowledges the batch only if every event has been acknowledged,
+	// otherwise the response carries the status of the first event not acknowledged,
+	// so that the sender retries the whole batch.
+	BatchAckAllOrNothing BatchAckMode = "all-or-nothing"
+	// BatchAckPartial responds with 207 Multi-Status when only some of the events have been acknowledged,
+	// reporting the result of each event in a JSON array of BatchItemResult, so that the sender can
+	// retry only the events not acknowledged.
+	BatchAckPartial BatchAckMode = "partial"
+)
+
+// BatchItemResult is the result of an event received in a batch,
+// reported in the body of a 207 Multi-Status response when using BatchAckPartial.
+type BatchItemResult struct {
+	ID     string `json:"id"`
+	Source string `json:"source"`
+	Status int    `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// batchItemMessage holds an event received in a batch request, exposing the context of the request.
+type batchItemMessage struct {
+	*binding.EventMessage
+	ctx context.Context
+}
+
+var (
+	_ binding.MessageContext = (*batchItemMessage)(nil)
+	_ binding.MessageWrapper = (*batchItemMessage)(nil)
+)
+
+func (m *batchItemMessage) Context() context.Context {
+	return m.ctx
+}
+
+func (m *batchItemMessage) GetWrappedMessage() binding.Message {
+	return m.EventMessage
+}
+
+// serveBatch splits a batch request in a message for each event and responds
+// once every message has been responded.
+func (p *Protocol) serveBatch(rw http.ResponseWriter, req *http.Request) {
+	events, err := NewEventsFromHTTPRequest(req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if isMaxBytesError(err) {
+			status = http.StatusRequestEntityTooLarge
+		} else if errors.Is(err, ErrUnsupportedContentEncoding) {
+			status = http.StatusUnsupportedMediaType
+		}
+		http.Error(rw, fmt.Sprintf("Cannot read CloudEvents batch: %s", err), status)
+		return
+	}
+
+	items := make([]BatchItemResult, len(events))
+	wg := sync.WaitGroup{}
+	wg.Add(len(events))
+	for i := range events {
+		items[i] = BatchItemResult{ID: events[i].ID(), Source: events[i].Source()}
+		var fn protocol.ResponseFn = func(ctx context.Context, respMsg binding.Message, res protocol.Result, transformers ...binding.Transformer) error {
+			defer wg.Done()
+			// The events received in a batch can't be responded with an event
+			if respMsg != nil {
+				_ = respMsg.Finish(nil)
+			}
+			items[i].Status, items[i].Error = resultStatus(res)
+			return nil
+		}
+		m := &batchItemMessage{EventMessage: (*binding.EventMessage)(&events[i]), ctx: req.Context()}
+		p.incoming <- msgErr{msg: m, respFn: fn}
+	}
+	// Block until ResponseFn is invoked for every event
+	wg.Wait()
+
+	failed := -1
+	acked := 0
+	for i, item := range items {
+		if item.Status/100 == 2 {
+			acked++
+		} else if failed == -1 {
+			failed = i
+		}
+	}
+
+	switch {
+	case failed == -1:
+		rw.WriteHeader(http.StatusOK)
+	case p.batchAckMode == BatchAckPartial && acked > 0:
+		rw.Header().Set(ContentType, event.ApplicationJSON)
+		rw.WriteHeader(http.StatusMultiStatus)
+		_ = json.NewEncoder(rw).Encode(items)
+	default:
+		item := items[failed]
+		http.Error(rw, fmt.Sprintf("Event %s from %s not acknowledged: %s", item.ID, item.Source, item.Error), item.Status)
+	}
+}
+
+// withItemResults maps the BatchItemResults in the body of a 207 Multi-Status response msg
+// to the events of the batch, returning a *protocol.BatchResult. The items are matched
+// to the events by id and source, the events without item are not acknowledged.
+func withItemResults(events []event.Event, msg binding.Message, err error) error {
+	var res *Result
+	if !protocol.ResultAs(err, &res) || res.StatusCode != http.StatusMultiStatus {
+		return err
+	}
+	message, ok := msg.(*Message)
+	if !ok || message.BodyReader == nil {
+		return err
+	}
+	var items []BatchItemResult
+	if decodeErr := json.NewDecoder(message.BodyReader).Decode(&items); decodeErr != nil {
+		return NewResult(res.StatusCode, "%w: failed to decode the results of the events: %v", protocol.ResultNACK, decodeErr)
+	}
+
+	type key struct{ id, source string }
+	indexes := make(map[key][]int, len(items))
+	for i, item := range items {
+		k := key{item.ID, item.Source}
+		indexes[k] = append(indexes[k], i)
+	}
+	results := make([]protocol.Result, len(events))
+	nacked := 0
+	for i, e := range events {
+		k := key{e.ID(), e.Source()}
+		found := indexes[k]
+		if len(found) == 0 {
+			results[i] = NewResult(res.StatusCode, "%w: no result reported for the event", protocol.ResultNACK)
+			nacked++
+			continue
+		}
+		item := items[found[0]]
+		indexes[k] = found[1:]
+		if item.Status/100 == 2 {
+			results[i] = NewResult(item.Status, "%w", protocol.ResultACK)
+			continue
+		}
+		results[i] = NewResult(item.Status, "%w: %s", protocol.ResultNACK, item.Error)
+		nacked++
+	}
+
+	batch := err
+	if nacked > 0 {
+		batch = NewResult(res.StatusCode, "%w: %d of %d events not acknowledged", protocol.ResultNACK, nacked, len(events))
+	}
+	return &protocol.BatchResult{Result: batch, Results: results}
+}
+
+// resultStatus maps the result of an event processing to a HTTP status code and error message,
+// like the response to a single event.
+func resultStatus(res protocol.Result) (int, string) {
+	if res == nil {
+		return http.StatusOK, ""
+	}
+	var result *Result
+	if protocol.ResultAs(res, &result) {
+		status := http.StatusOK
+		if result.StatusCode > 100 && result.StatusCode < 600 {
+			status = result.StatusCode
+		}
+		return status, fmt.Errorf(result.Format, result.Args...).Error()
+	}
+	if protocol.IsACK(res) {
+		return http.StatusOK, ""
+	}
+	return nackStatus(res), res.Error()
+}
+
+// nackStatus maps a result not acknowledging a message to a HTTP status code.
+func nackStatus(res protocol.Result) int {
+	validationError := event.ValidationError{}
+	switch {
+	case errors.As(res, &validationError):
+		return http.StatusBadRequest
+	case errors.Is(res, binding.ErrUnknownEncoding), errors.Is(res, ErrUnsupportedContentEncoding):
+		return http.StatusUnsupportedMediaType
+	case isMaxBytesError(res):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// isMaxBytesError returns whether err is caused by a body larger than WithMaxBodySize.
+func isMaxBytesError(err error) bool {
+	var maxBytesError *http.MaxBytesError
+	return errors.As(err, &maxBytesError)
+}
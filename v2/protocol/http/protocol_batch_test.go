@@ -0,0 +1,176 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+func batchRequest(t *testing.T, n int) *http.Request {
+	events := make([]event.Event, n)
+	for i := range events {
+		events[i] = event.New()
+		events[i].SetID(strconv.Itoa(i))
+		events[i].SetType("unit.test.protocol")
+		events[i].SetSource("/unit/test/protocol")
+	}
+	req, err := NewHTTPRequestFromEvents(context.Background(), "http://unittest", events)
+	require.NoError(t, err)
+	return req
+}
+
+// respondBatch responds n messages, NACKing the events whose id is in nack.
+func respondBatch(t *testing.T, p *Protocol, n int, nack map[string]protocol.Result) []string {
+	var ids []string
+	for i := 0; i < n; i++ {
+		m, fn, err := p.Respond(context.Background())
+		require.NoError(t, err)
+		e, err := binding.ToEvent(context.Background(), m)
+		require.NoError(t, err)
+		ids = append(ids, e.ID())
+		require.NoError(t, fn(context.Background(), nil, nack[e.ID()]))
+	}
+	return ids
+}
+
+func TestServeHTTP_ReceiveBatch(t *testing.T) {
+	testCases := map[string]struct {
+		mode       BatchAckMode
+		nack       map[string]protocol.Result
+		wantStatus int
+		wantItems  []BatchItemResult
+	}{
+		"all acked": {
+			mode:       BatchAckAllOrNothing,
+			wantStatus: http.StatusOK,
+		},
+		"all-or-nothing with a nack": {
+			mode:       BatchAckAllOrNothing,
+			nack:       map[string]protocol.Result{"1": NewResult(http.StatusServiceUnavailable, "unavailable")},
+			wantStatus: http.StatusServiceUnavailable,
+		},
+		"partial with a nack": {
+			mode:       BatchAckPartial,
+			nack:       map[string]protocol.Result{"1": protocol.ResultNACK},
+			wantStatus: http.StatusMultiStatus,
+			wantItems: []BatchItemResult{
+				{ID: "0", Source: "/unit/test/protocol", Status: http.StatusOK},
+				{ID: "1", Source: "/unit/test/protocol", Status: http.StatusInternalServerError, Error: protocol.ResultNACK.Error()},
+				{ID: "2", Source: "/unit/test/protocol", Status: http.StatusOK},
+			},
+		},
+		"partial with only nacks": {
+			mode: BatchAckPartial,
+			nack: map[string]protocol.Result{
+				"0": NewResult(http.StatusBadRequest, "bad"),
+				"1": NewResult(http.StatusBadRequest, "bad"),
+				"2": NewResult(http.StatusBadRequest, "bad"),
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			p, err := New(WithBatchAckMode(tc.mode))
+			require.NoError(t, err)
+
+			rec := httptest.NewRecorder()
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				p.ServeHTTP(rec, batchRequest(t, 3))
+			}()
+
+			// Every event of the batch is received as a message on its own
+			require.Equal(t, []string{"0", "1", "2"}, respondBatch(t, p, 3, tc.nack))
+			<-done
+
+			require.Equal(t, tc.wantStatus, rec.Code)
+			if tc.wantItems != nil {
+				var items []BatchItemResult
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &items))
+				require.Equal(t, tc.wantItems, items)
+			}
+		})
+	}
+}
+
+func TestServeHTTP_ReceiveMalformedBatch(t *testing.T) {
+	p, err := New()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "http://unittest", nil)
+	req.Header.Set(ContentType, event.ApplicationCloudEventsBatchJSON)
+	req.Body = http.NoBody
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestSendBatchToPartialReceiver(t *testing.T) {
+	receiver, err := New(WithBatchAckMode(BatchAckPartial))
+	require.NoError(t, err)
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	events := make([]event.Event, 4)
+	for i := range events {
+		events[i] = test.MinEvent()
+		events[i].SetID(strconv.Itoa(i))
+	}
+	// The same id from another source
+	events[3].SetID("1")
+	events[3].SetSource("/other")
+
+	// Reject the event 1 from the default source
+	go func() {
+		for range events {
+			m, fn, err := receiver.Respond(context.Background())
+			if err != nil {
+				return
+			}
+			e, _ := binding.ToEvent(context.Background(), m)
+			var result protocol.Result
+			if e.ID() == "1" && e.Source() == test.Source.String() {
+				result = NewResult(http.StatusBadRequest, "rejected")
+			}
+			_ = fn(context.Background(), nil, result)
+		}
+	}()
+
+	sender, err := New(WithTarget(server.URL))
+	require.NoError(t, err)
+	result := sender.SendBatch(context.Background(), events)
+
+	require.False(t, protocol.IsACK(result), "unexpected result %v", result)
+	var batchResult *protocol.BatchResult
+	require.True(t, protocol.ResultAs(result, &batchResult), "unexpected result %v", result)
+	require.Len(t, batchResult.Results, len(events))
+	for i, want := range []int{http.StatusOK, http.StatusBadRequest, http.StatusOK, http.StatusOK} {
+		eventResult := protocol.BatchEventResult(result, i)
+		var res *Result
+		require.True(t, protocol.ResultAs(eventResult, &res), "unexpected result %v", eventResult)
+		require.Equal(t, want, res.StatusCode, "event %d", i)
+		require.Equal(t, want == http.StatusOK, protocol.IsACK(eventResult), "event %d", i)
+	}
+}
+
+func TestWithBatchAckMode(t *testing.T) {
+	_, err := New(WithBatchAckMode("unknown"))
+	require.Error(t, err)
+}
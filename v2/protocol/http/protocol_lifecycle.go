@@ -15,12 +15,32 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
-var _ protocol.Opener = (*Protocol)(nil)
+var (
+	_ protocol.Opener         = (*Protocol)(nil)
+	_ protocol.InboundStopper = (*Protocol)(nil)
+)
 
 func (p *Protocol) OpenInbound(ctx context.Context) error {
+	if p.routed {
+		// The route is served by its Router
+		<-ctx.Done()
+		return nil
+	}
+
 	p.reMu.Lock()
 	defer p.reMu.Unlock()
 
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	p.stopMu.Lock()
+	p.stopInbound = cancel
+	p.stopMu.Unlock()
+	defer func() {
+		p.stopMu.Lock()
+		p.stopInbound = nil
+		p.stopMu.Unlock()
+	}()
+
 	if p.Handler == nil {
 		p.Handler = http.NewServeMux()
 	}
@@ -31,17 +51,27 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 		p.handlerRegistered = true
 	}
 
-	// After listener is invok
-	listener, err := p.listen()
-	if err != nil {
-		return err
-	}
+	handler := attachMiddleware(p.Handler, p.middleware)
+	if p.inboundServer != nil {
+		p.server = p.inboundServer
+	} else {
+		// After listener is invok
+		listener, err := p.listen()
+		if err != nil {
+			return err
+		}
 
-	p.server = &http.Server{
-		Addr:         listener.Addr().String(),
-		Handler:      attachMiddleware(p.Handler, p.middleware),
-		ReadTimeout:  *p.readTimeout,
-		WriteTimeout: *p.writeTimeout,
+		p.server = &listenerServer{
+			listener: listener,
+			server: &http.Server{
+				Addr:         listener.Addr().String(),
+				ReadTimeout:  *p.readTimeout,
+				WriteTimeout: *p.writeTimeout,
+				Protocols:    p.serverProtocols,
+				HTTP2:        p.serverHTTP2Config,
+				TLSConfig:    p.serverTLSConfig(),
+			},
+		}
 	}
 
 	// Shutdown
@@ -52,7 +82,7 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 
 	errChan := make(chan error)
 	go func() {
-		errChan <- p.server.Serve(listener)
+		errChan <- p.server.Serve(handler)
 	}()
 
 	// wait for the server to return or ctx.Done().
@@ -90,6 +120,52 @@ func (p *Protocol) OpenInbound(ctx context.Context) error {
 	}
 }
 
+// StopInbound gracefully shuts down the server started by OpenInbound, which returns once the active
+// requests have been responded or the ShutdownTimeout expired. The routes served by a Router are not stopped.
+func (p *Protocol) StopInbound(context.Context) error {
+	p.stopMu.Lock()
+	defer p.stopMu.Unlock()
+	if p.stopInbound != nil {
+		p.stopInbound()
+	}
+	return nil
+}
+
+// InboundServer serves the handler of the Protocol in place of the default http.Server
+// listening on Port, e.g. to receive events over HTTP/3.
+type InboundServer interface {
+	// Serve serves the handler, blocking until the server is shut down or closed.
+	// After Shutdown or Close, Serve returns http.ErrServerClosed.
+	Serve(handler http.Handler) error
+	// Shutdown gracefully shuts down the server, waiting for the active requests to complete.
+	Shutdown(ctx context.Context) error
+	// Close immediately closes the server.
+	Close() error
+}
+
+// listenerServer is the default InboundServer, serving the handler with a http.Server on a listener.
+type listenerServer struct {
+	listener net.Listener
+	server   *http.Server
+}
+
+func (s *listenerServer) Serve(handler http.Handler) error {
+	s.server.Handler = handler
+	if s.server.TLSConfig != nil {
+		// The certificates are provided by the TLS config
+		return s.server.ServeTLS(s.listener, "", "")
+	}
+	return s.server.Serve(s.listener)
+}
+
+func (s *listenerServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *listenerServer) Close() error {
+	return s.server.Close()
+}
+
 // GetListeningPort returns the listening port.
 // Returns -1 if it's not listening.
 func (p *Protocol) GetListeningPort() int {
@@ -0,0 +1,125 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+// openAndRespond opens p and ACKs every received message, recording the protocol of the requests.
+func openAndRespond(t *testing.T, ctx context.Context, p *Protocol) (protos <-chan string, done <-chan error) {
+	ch := make(chan string, 10)
+	p.middleware = append(p.middleware, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			ch <- req.Proto
+			next.ServeHTTP(rw, req)
+		})
+	})
+	opened := make(chan error, 1)
+	go func() {
+		opened <- p.OpenInbound(ctx)
+	}()
+	go func() {
+		for {
+			m, fn, err := p.Respond(ctx)
+			if err != nil {
+				return
+			}
+			_ = m.Finish(nil)
+			_ = fn(ctx, nil, nil)
+		}
+	}()
+	return ch, opened
+}
+
+func TestH2C(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver, err := New(WithListener(listener), WithH2C(&http.HTTP2Config{MaxConcurrentStreams: 10}))
+	require.NoError(t, err)
+	protos, done := openAndRespond(t, ctx, receiver)
+
+	target := "http://" + listener.Addr().String()
+
+	t.Run("h2c sender", func(t *testing.T) {
+		sender, err := New(WithTarget(target), WithH2CTransport(nil))
+		require.NoError(t, err)
+		err = sender.Send(ctx, test.MinMessage())
+		require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+		require.Equal(t, "HTTP/2.0", <-protos)
+	})
+
+	t.Run("http/1.1 sender", func(t *testing.T) {
+		sender, err := New(WithTarget(target))
+		require.NoError(t, err)
+		err = sender.Send(ctx, test.MinMessage())
+		require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+		require.Equal(t, "HTTP/1.1", <-protos)
+	})
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+// recordingServer is an InboundServer serving the handler with a http.Server on a listener,
+// recording the calls.
+type recordingServer struct {
+	listenerServer
+	mu    sync.Mutex
+	calls []string
+}
+
+func (s *recordingServer) record(call string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+}
+
+func (s *recordingServer) Serve(handler http.Handler) error {
+	s.record("serve")
+	return s.listenerServer.Serve(handler)
+}
+
+func (s *recordingServer) Shutdown(ctx context.Context) error {
+	s.record("shutdown")
+	return s.listenerServer.Shutdown(ctx)
+}
+
+func TestWithInboundServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &recordingServer{listenerServer: listenerServer{listener: listener, server: &http.Server{}}}
+	receiver, err := New(WithInboundServer(server))
+	require.NoError(t, err)
+	_, done := openAndRespond(t, ctx, receiver)
+
+	sender, err := New(WithTarget("http://" + listener.Addr().String()))
+	require.NoError(t, err)
+	err = sender.Send(ctx, test.MinMessage())
+	require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+
+	cancel()
+	require.NoError(t, <-done)
+	require.Equal(t, []string{"serve", "shutdown"}, server.calls)
+
+	_, err = New(WithInboundServer(nil))
+	require.Error(t, err)
+}
@@ -6,8 +6,21 @@
 package http
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"math"
+	"net"
 	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
+	"github.com/cloudevents/sdk-go/v2/types"
 )
 
 type RateLimiter interface {
@@ -32,3 +45,214 @@ func (n noOpLimiter) Allow(ctx context.Context, r *http.Request) (bool, uint64,
 func (n noOpLimiter) Close(ctx context.Context) error {
 	return nil
 }
+
+// AllowedRateLimiter is implemented by the RateLimiters advertising the rate they allow to
+// each client in the WebHook-Allowed-Rate header of OptionsHandler.
+type AllowedRateLimiter interface {
+	RateLimiter
+	// AllowedRate returns the allowed rate in requests per minute, or 0 if it is unlimited.
+	AllowedRate() int
+}
+
+// NewRateLimiter returns a token bucket RateLimiter allowing r requests per second,
+// with bursts of at most burst requests, to all the clients.
+func NewRateLimiter(r rate.Limit, burst int) RateLimiter {
+	return NewKeyedRateLimiter(func(*http.Request) (string, error) {
+		return "", nil
+	}, r, burst)
+}
+
+// NewClientIPRateLimiter returns a RateLimiter with a token bucket allowing r requests
+// per second, with bursts of at most burst requests, to each client IP.
+// The IP is the remote address of the connection, use NewKeyedRateLimiter to identify
+// the clients differently, e.g. behind a reverse proxy.
+func NewClientIPRateLimiter(r rate.Limit, burst int) RateLimiter {
+	return NewKeyedRateLimiter(clientIP, r, burst)
+}
+
+// NewAttributeRateLimiter returns a RateLimiter with a token bucket allowing r requests
+// per second, with bursts of at most burst requests, to each value of the CloudEvents
+// attribute or extension, e.g. "source".
+// In structured mode the request body is buffered and decoded to read the attribute, only
+// when its size is limited with WithMaxBodySize; for a batch, the attribute of the first event
+// is used. The requests which can't be decoded, like the structured requests when the body
+// size isn't limited, share the bucket of the empty value.
+func NewAttributeRateLimiter(attribute string, r rate.Limit, burst int) RateLimiter {
+	return NewKeyedRateLimiter(func(req *http.Request) (string, error) {
+		return eventAttribute(req, attribute)
+	}, r, burst)
+}
+
+// NewKeyedRateLimiter returns a RateLimiter with a token bucket allowing r requests per
+// second, with bursts of at most burst requests, to each key returned by key for the
+// requests.
+func NewKeyedRateLimiter(key func(r *http.Request) (string, error), r rate.Limit, burst int) RateLimiter {
+	return &keyedLimiter{
+		key:     key,
+		limit:   r,
+		burst:   burst,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// bucketPurgeInterval is the interval between the removals of the idle buckets.
+const bucketPurgeInterval = time.Minute
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type keyedLimiter struct {
+	key   func(r *http.Request) (string, error)
+	limit rate.Limit
+	burst int
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	purged  time.Time
+	closed  bool
+}
+
+var _ AllowedRateLimiter = (*keyedLimiter)(nil)
+
+func (l *keyedLimiter) Allow(ctx context.Context, r *http.Request) (bool, uint64, error) {
+	key, err := l.key(r)
+	if err != nil {
+		return false, 0, err
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return false, 0, nil
+	}
+	l.purge(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	l.mu.Unlock()
+
+	reservation := b.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		// Never allowed, e.g. with a burst of 0
+		return false, uint64(bucketPurgeInterval.Seconds()), nil
+	}
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		// Retry-After is in seconds, round up so that the next attempt is allowed
+		return false, uint64(math.Ceil(delay.Seconds())), nil
+	}
+	return true, 0, nil
+}
+
+// purge removes the buckets idle for long enough to be full again, which are equivalent
+// to new ones.
+func (l *keyedLimiter) purge(now time.Time) {
+	if now.Sub(l.purged) < bucketPurgeInterval {
+		return
+	}
+	l.purged = now
+	refill := bucketPurgeInterval
+	if l.limit > 0 && l.limit != rate.Inf {
+		refill = max(refill, time.Duration(float64(l.burst)/float64(l.limit)*float64(time.Second)))
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > refill {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *keyedLimiter) Close(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.closed = true
+	l.buckets = nil
+	return nil
+}
+
+// AllowedRate implements AllowedRateLimiter.
+func (l *keyedLimiter) AllowedRate() int {
+	if l.limit == rate.Inf || l.limit <= 0 {
+		return 0
+	}
+	return max(1, int(float64(l.limit)*60))
+}
+
+// clientIP returns the IP of the client of r.
+func clientIP(r *http.Request) (string, error) {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr, nil
+	}
+	return host, nil
+}
+
+// maxBodySizeKey is the context key of the maximum size of the body of the request,
+// set by the Protocol with WithMaxBodySize for the rate limiters.
+type maxBodySizeKey struct{}
+
+// eventAttribute returns the value of the CloudEvents attribute or extension of the event
+// in r, keeping the body readable.
+func eventAttribute(r *http.Request, attribute string) (string, error) {
+	if NewMessage(r.Header, nil).ReadEncoding() == binding.EncodingBinary {
+		if attribute == "datacontenttype" {
+			return r.Header.Get(ContentType), nil
+		}
+		return r.Header.Get(prefix + attribute), nil
+	}
+
+	// Read the body only when its size is bounded
+	limit, _ := r.Context().Value(maxBodySizeKey{}).(int64)
+	if r.Body == nil || r.Body == http.NoBody || limit <= 0 || r.ContentLength > limit {
+		return "", nil
+	}
+	var raw bytes.Buffer
+	decoded := decodeBody(r.Header, io.NopCloser(io.TeeReader(http.MaxBytesReader(nil, r.Body, limit), &raw)))
+	body, err := io.ReadAll(http.MaxBytesReader(nil, decoded, limit))
+	// Put back what has been read, the handler reads the body again from the start
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(raw.Bytes()), r.Body), r.Body}
+	if err != nil {
+		// Let the handler fail reading the body
+		return "", nil
+	}
+
+	header := r.Header
+	if isContentEncoded(header) {
+		header = header.Clone()
+		header.Del(ContentEncoding)
+	}
+	m := NewMessage(header, nil)
+	var e *event.Event
+	if IsHTTPBatch(r.Header) {
+		events, err := binding.ToEvents(r.Context(), m, io.NopCloser(bytes.NewReader(body)))
+		if err != nil || len(events) == 0 {
+			return "", nil
+		}
+		e = &events[0]
+	} else {
+		m.BodyReader = io.NopCloser(bytes.NewReader(body))
+		if e, err = binding.ToEvent(r.Context(), m); err != nil {
+			return "", nil
+		}
+	}
+
+	value := e.Extensions()[attribute]
+	if v := spec.VS.Version(e.SpecVersion()); v != nil {
+		if a := v.Attribute(attribute); a != nil {
+			value = a.Get(e.Context)
+		}
+	}
+	if value == nil {
+		return "", nil
+	}
+	return types.Format(value)
+}
@@ -0,0 +1,217 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+)
+
+type allowResult struct {
+	ok    bool
+	reset uint64
+}
+
+func allow(t *testing.T, limiter RateLimiter, req *http.Request) allowResult {
+	t.Helper()
+	ok, reset, err := limiter.Allow(context.Background(), req)
+	require.NoError(t, err)
+	return allowResult{ok: ok, reset: reset}
+}
+
+func newRateRequest(remoteAddr string, source string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/", nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", "1")
+	req.Header.Set("Ce-Type", "unit.test")
+	req.Header.Set("Ce-Source", source)
+	return req
+}
+
+func TestRateLimiter(t *testing.T) {
+	limiter := NewRateLimiter(rate.Every(10*time.Second), 2)
+
+	require.Equal(t, allowResult{ok: true}, allow(t, limiter, newRateRequest("10.0.0.1:1", "a")))
+	require.Equal(t, allowResult{ok: true}, allow(t, limiter, newRateRequest("10.0.0.2:1", "b")))
+	require.Equal(t, allowResult{ok: false, reset: 10}, allow(t, limiter, newRateRequest("10.0.0.3:1", "c")))
+
+	require.NoError(t, limiter.Close(context.Background()))
+	require.Equal(t, allowResult{}, allow(t, limiter, newRateRequest("10.0.0.4:1", "d")))
+}
+
+func TestClientIPRateLimiter(t *testing.T) {
+	limiter := NewClientIPRateLimiter(rate.Every(time.Minute), 1)
+
+	require.Equal(t, allowResult{ok: true}, allow(t, limiter, newRateRequest("10.0.0.1:1", "a")))
+	require.Equal(t, allowResult{ok: true}, allow(t, limiter, newRateRequest("10.0.0.2:1", "a")))
+	// Another port of the same client
+	require.Equal(t, allowResult{ok: false, reset: 60}, allow(t, limiter, newRateRequest("10.0.0.1:2", "b")))
+}
+
+func TestAttributeRateLimiter(t *testing.T) {
+	limiter := NewAttributeRateLimiter("source", rate.Every(time.Minute), 1)
+
+	t.Run("binary", func(t *testing.T) {
+		require.True(t, allow(t, limiter, newRateRequest("10.0.0.1:1", "/binary")).ok)
+		require.False(t, allow(t, limiter, newRateRequest("10.0.0.2:1", "/binary")).ok)
+		require.True(t, allow(t, limiter, newRateRequest("10.0.0.1:1", "/other")).ok)
+	})
+
+	structured := func(contentType, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set(ContentType, contentType)
+		// As set by the Protocol with WithMaxBodySize
+		return req.WithContext(context.WithValue(req.Context(), maxBodySizeKey{}, int64(1024)))
+	}
+
+	t.Run("structured", func(t *testing.T) {
+		body := `{"specversion":"1.0","id":"1","type":"unit.test","source":"/structured"}`
+		req := structured("application/cloudevents+json", body)
+		require.True(t, allow(t, limiter, req).ok)
+		// The body can still be read
+		read, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, body, string(read))
+		require.False(t, allow(t, limiter, structured("application/cloudevents+json", body)).ok)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		body := `[{"specversion":"1.0","id":"1","type":"unit.test","source":"/batch"}]`
+		require.True(t, allow(t, limiter, structured("application/cloudevents-batch+json", body)).ok)
+		require.False(t, allow(t, limiter, structured("application/cloudevents-batch+json", body)).ok)
+	})
+
+	t.Run("compressed", func(t *testing.T) {
+		var body bytes.Buffer
+		gw := gzip.NewWriter(&body)
+		_, err := gw.Write([]byte(`{"specversion":"1.0","id":"1","type":"unit.test","source":"/compressed"}`))
+		require.NoError(t, err)
+		require.NoError(t, gw.Close())
+		compressed := body.String()
+		newRequest := func() *http.Request {
+			req := structured("application/cloudevents+json", compressed)
+			req.Header.Set(ContentEncoding, ContentEncodingGzip)
+			return req
+		}
+
+		req := newRequest()
+		require.True(t, allow(t, limiter, req).ok)
+		// The body can still be read, compressed
+		read, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Equal(t, compressed, string(read))
+		require.False(t, allow(t, limiter, newRequest()).ok)
+	})
+
+	t.Run("unbounded body", func(t *testing.T) {
+		limiter := NewAttributeRateLimiter("source", rate.Every(time.Minute), 1)
+		newRequest := func(source string) *http.Request {
+			body := `{"specversion":"1.0","id":"1","type":"unit.test","source":"` + source + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set(ContentType, "application/cloudevents+json")
+			return req
+		}
+		// Without body size limit, the structured requests aren't read and share the same bucket
+		req := newRequest("/unbounded")
+		require.True(t, allow(t, limiter, req).ok)
+		read, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		require.Contains(t, string(read), "/unbounded")
+		require.False(t, allow(t, limiter, newRequest("/other")).ok)
+	})
+
+	t.Run("extension", func(t *testing.T) {
+		limiter := NewAttributeRateLimiter("tenant", rate.Every(time.Minute), 1)
+		req := newRateRequest("10.0.0.1:1", "/extension")
+		req.Header.Set("Ce-Tenant", "a")
+		require.True(t, allow(t, limiter, req).ok)
+		require.False(t, allow(t, limiter, req).ok)
+	})
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.read += n
+	return n, err
+}
+
+func TestAttributeRateLimiterMaxBodySize(t *testing.T) {
+	serve := func(body *countingReader, chunked bool) int {
+		p, err := New(
+			WithRateLimiter(NewAttributeRateLimiter("source", rate.Every(time.Minute), 1)),
+			WithMaxBodySize(1024),
+		)
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/", body)
+		req.Header.Set(ContentType, "application/cloudevents-batch+json")
+		if chunked {
+			req.ContentLength = -1
+		} else {
+			req.ContentLength = 1024 * 1024
+		}
+		rw := httptest.NewRecorder()
+		p.ServeHTTP(rw, req)
+		return rw.Code
+	}
+	oversized := func() *countingReader {
+		event := `{"specversion":"1.0","id":"1","type":"unit.test","source":"/oversized"},`
+		return &countingReader{r: strings.NewReader("[" + strings.Repeat(event, 1024*1024/len(event)) + "]")}
+	}
+
+	t.Run("content length", func(t *testing.T) {
+		body := oversized()
+		require.Equal(t, http.StatusRequestEntityTooLarge, serve(body, false))
+		require.Zero(t, body.read)
+	})
+
+	t.Run("chunked", func(t *testing.T) {
+		body := oversized()
+		require.Equal(t, http.StatusRequestEntityTooLarge, serve(body, true))
+		// The body is read up to the limit, by both the rate limiter and the handler
+		require.Less(t, body.read, 8*1024)
+	})
+}
+
+func TestRateLimiterAllowedRate(t *testing.T) {
+	p, err := New(
+		WithRateLimiter(NewClientIPRateLimiter(rate.Every(time.Minute), 1)),
+		WithDefaultOptionsHandlerFunc([]string{http.MethodPost}, 0, []string{"*"}, false),
+	)
+	require.NoError(t, err)
+
+	options := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodOptions, "/", nil)
+		req.Header.Set("WebHook-Request-Origin", "http://sender.example.com")
+		rw := httptest.NewRecorder()
+		p.ServeHTTP(rw, req)
+		return rw
+	}
+
+	rw := options()
+	require.Equal(t, http.StatusOK, rw.Code)
+	require.Equal(t, "1", rw.Header().Get("WebHook-Allowed-Rate"))
+
+	rw = options()
+	require.Equal(t, http.StatusTooManyRequests, rw.Code)
+	require.Equal(t, "60", rw.Header().Get("Retry-After"))
+}
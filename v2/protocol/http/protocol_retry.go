@@ -11,6 +11,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"strconv"
 	"time"
 
 	"go.uber.org/zap"
@@ -23,17 +24,20 @@ import (
 func (p *Protocol) do(ctx context.Context, req *http.Request) (binding.Message, error) {
 	params := cecontext.RetriesFrom(ctx)
 
-	switch params.Strategy {
-	case cecontext.BackoffStrategyConstant, cecontext.BackoffStrategyLinear, cecontext.BackoffStrategyExponential:
+	if params.IsRetrying() {
 		return p.doWithRetry(ctx, params, req)
-	case cecontext.BackoffStrategyNone:
-		fallthrough
-	default:
-		return p.doOnce(req)
 	}
+	return p.doOnce(req)
 }
 
 func (p *Protocol) doOnce(req *http.Request) (binding.Message, protocol.Result) {
+	if err := p.waitWebhookRate(req); err != nil {
+		return nil, protocol.NewReceipt(false, "%w", err)
+	}
+	if err := p.transformRequest(req); err != nil {
+		return nil, protocol.NewReceipt(false, "%w", err)
+	}
+
 	resp, err := p.Client.Do(req)
 	if err != nil {
 		return nil, protocol.NewReceipt(false, "%w", err)
@@ -80,6 +84,7 @@ func (p *Protocol) doWithRetry(ctx context.Context, params *cecontext.RetryParam
 			return msg, NewRetriesResult(result, retry, start, results)
 		}
 
+		var retryAfter time.Duration
 		var httpResult *Result
 		if errors.As(result, &httpResult) {
 			sc := httpResult.StatusCode
@@ -89,10 +94,13 @@ func (p *Protocol) doWithRetry(ctx context.Context, params *cecontext.RetryParam
 					zap.Int("statusCode", sc))
 				return msg, NewRetriesResult(result, retry, start, results)
 			}
+			if m, ok := msg.(*Message); ok && (sc == http.StatusTooManyRequests || sc == http.StatusServiceUnavailable) {
+				retryAfter = parseRetryAfter(m.Header.Get("Retry-After"), time.Now())
+			}
 		}
 
 		// total tries = retry + 1
-		if err = params.Backoff(ctx, retry+1); err != nil {
+		if err = params.BackoffSince(ctx, start, retry+1, retryAfter); err != nil {
 			// do not try again.
 			cecontext.LoggerFrom(ctx).Debugw("backoff error, will not try again", zap.Error(err))
 			return msg, NewRetriesResult(result, retry, start, results)
@@ -108,6 +116,26 @@ func (p *Protocol) doWithRetry(ctx context.Context, params *cecontext.RetryParam
 	}
 }
 
+// parseRetryAfter returns the delay requested by a Retry-After header value,
+// which is either a number of seconds or an HTTP date. It returns 0 if the value is missing or invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := date.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
 // reset body to allow it to be read multiple times, e.g. when retrying http
 // requests
 func resetBody(req *http.Request, body []byte) {
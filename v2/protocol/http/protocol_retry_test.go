@@ -224,3 +224,50 @@ func newEvent(t *testing.T, encoding string, body interface{}) event.Event {
 
 	return e
 }
+
+func TestRequestWithRetries_retryAfter(t *testing.T) {
+	var count int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	p, err := New()
+	require.NoError(t, err)
+
+	ctx := cecontext.WithTarget(context.Background(), srv.URL)
+	ctx = cecontext.WithRetriesConstantBackoff(ctx, time.Millisecond, 3)
+
+	e := newEvent(t, event.ApplicationJSON, map[string]string{"hello": "world"})
+	start := time.Now()
+	_, got := p.Request(ctx, binding.ToMessage(&e))
+	require.True(t, protocol.IsACK(got))
+	require.Equal(t, 2, count)
+	require.GreaterOrEqual(t, time.Since(start), time.Second)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	testCases := map[string]struct {
+		value string
+		want  time.Duration
+	}{
+		"empty":    {value: "", want: 0},
+		"seconds":  {value: "120", want: 2 * time.Minute},
+		"negative": {value: "-1", want: 0},
+		"date":     {value: now.Add(30 * time.Second).Format(http.TimeFormat), want: 30 * time.Second},
+		"past":     {value: now.Add(-30 * time.Second).Format(http.TimeFormat), want: 0},
+		"invalid":  {value: "soon", want: 0},
+	}
+	for n, tc := range testCases {
+		t.Run(n, func(t *testing.T) {
+			require.Equal(t, tc.want, parseRetryAfter(tc.value, now))
+		})
+	}
+}
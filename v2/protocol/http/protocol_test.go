@@ -7,9 +7,12 @@ package http
 
 import (
 	"context"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -21,6 +24,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/protocol"
 )
 
@@ -139,6 +143,60 @@ func TestRequest(t *testing.T) {
 	}
 }
 
+func TestSendBatch(t *testing.T) {
+	var gotContentType string
+	var got []event.Event
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		gotContentType = req.Header.Get(ContentType)
+		var err error
+		if got, err = NewEventsFromHTTPRequest(req); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if len(got) > 2 {
+			rw.WriteHeader(http.StatusRequestEntityTooLarge)
+			_, _ = rw.Write([]byte("too many events"))
+			return
+		}
+		rw.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	p, err := New(WithTarget(server.URL))
+	require.NoError(t, err)
+
+	events := make([]event.Event, 3)
+	for i := range events {
+		events[i] = event.New()
+		events[i].SetID(strconv.Itoa(i))
+		events[i].SetType("unit.test.protocol")
+		events[i].SetSource("/unit/test/protocol")
+		require.NoError(t, events[i].SetData(event.ApplicationJSON, map[string]int{"i": i}))
+	}
+
+	t.Run("ack", func(t *testing.T) {
+		err := p.SendBatch(context.Background(), events[:2])
+		require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+		require.Equal(t, event.ApplicationCloudEventsBatchJSON, gotContentType)
+		require.Len(t, got, 2)
+		require.Equal(t, events[0].ID(), got[0].ID())
+		require.Equal(t, events[1].Data(), got[1].Data())
+	})
+
+	t.Run("nack with response body", func(t *testing.T) {
+		err := p.SendBatch(context.Background(), events)
+		require.True(t, protocol.IsNACK(err), "unexpected result %v", err)
+		var res *Result
+		require.True(t, protocol.ResultAs(err, &res))
+		require.Equal(t, http.StatusRequestEntityTooLarge, res.StatusCode)
+		require.Contains(t, err.Error(), "too many events")
+	})
+
+	t.Run("empty batch", func(t *testing.T) {
+		require.EqualError(t, p.SendBatch(context.Background(), nil), "empty batch")
+	})
+}
+
 func TestReceive(t *testing.T) {
 	testCases := map[string]struct {
 		ctx     context.Context
@@ -268,6 +326,103 @@ func ReceiveTest(t *testing.T, p *Protocol, ctx context.Context, rec *httptest.R
 	}
 }
 
+func TestServeHTTP_MaxBodySize(t *testing.T) {
+	ctx := context.Background()
+	newRequest := func(header http.Header, body string, chunked bool) *http.Request {
+		req := httptest.NewRequest("POST", "http://unittest", strings.NewReader(body))
+		req.Header = header
+		if chunked {
+			req.ContentLength = -1
+		}
+		return req
+	}
+	binary := http.Header{
+		"Ce-Specversion": {"1.0"},
+		"Ce-Id":          {"1"},
+		"Ce-Type":        {"unit.test"},
+		"Ce-Source":      {"/unit/test"},
+	}
+	batch := http.Header{"Content-Type": {"application/cloudevents-batch+json"}}
+
+	_, err := New(WithMaxBodySize(0))
+	require.Error(t, err)
+
+	p, err := New(WithMaxBodySize(10))
+	require.NoError(t, err)
+
+	t.Run("content length", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		p.ServeHTTP(rw, newRequest(binary, strings.Repeat("a", 11), false))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+	})
+
+	t.Run("chunked", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		go p.ServeHTTP(rw, newRequest(binary, strings.Repeat("a", 11), true))
+		m, fn, err := p.Respond(ctx)
+		require.NoError(t, err)
+		_, err = binding.ToEvent(ctx, m)
+		var maxBytesError *http.MaxBytesError
+		require.True(t, errors.As(err, &maxBytesError), "unexpected error %v", err)
+		require.NoError(t, m.Finish(nil))
+		_ = fn(ctx, nil, protocol.NewReceipt(false, "%w", err))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+	})
+
+	t.Run("chunked batch", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		p.ServeHTTP(rw, newRequest(batch, `[{"specversion":"1.0"}]`, true))
+		require.Equal(t, http.StatusRequestEntityTooLarge, rw.Code)
+	})
+
+	t.Run("small enough", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		go p.ServeHTTP(rw, newRequest(binary, strings.Repeat("a", 10), true))
+		m, fn, err := p.Respond(ctx)
+		require.NoError(t, err)
+		e, err := binding.ToEvent(ctx, m)
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat("a", 10), string(e.Data()))
+		require.NoError(t, m.Finish(nil))
+		_ = fn(ctx, nil, nil)
+		require.Equal(t, http.StatusOK, rw.Code)
+	})
+}
+
+func TestSend_PipedBody(t *testing.T) {
+	ctx := context.Background()
+	body := strings.Repeat("a", 1<<20)
+
+	type received struct {
+		contentLength    int64
+		transferEncoding []string
+		body             string
+	}
+	ch := make(chan received, 1)
+	target := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		b, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		ch <- received{req.ContentLength, req.TransferEncoding, string(b)}
+	}))
+	defer target.Close()
+
+	req := httptest.NewRequest("POST", "http://unittest", strings.NewReader(body))
+	req.Header.Set("Ce-Specversion", "1.0")
+	req.Header.Set("Ce-Id", "1")
+	req.Header.Set("Ce-Type", "unit.test")
+	req.Header.Set("Ce-Source", "/unit/test")
+
+	sender, err := New(WithTarget(target.URL))
+	require.NoError(t, err)
+	err = sender.Send(ctx, NewMessageFromHttpRequest(req))
+	require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+
+	got := <-ch
+	require.Equal(t, int64(len(body)), got.contentLength)
+	require.Empty(t, got.transferEncoding)
+	require.Equal(t, body, got.body)
+}
+
 func TestServeHTTP_ReceiveWithLimiter(t *testing.T) {
 	testCases := map[string]struct {
 		limiter RateLimiter
@@ -0,0 +1,120 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// serverTLSConfig returns the TLS config of the server, or nil if the server doesn't use TLS.
+func (p *Protocol) serverTLSConfig() *tls.Config {
+	if p.tlsConfig == nil && p.certificates == nil && p.clientCAs == nil {
+		return nil
+	}
+	config := &tls.Config{}
+	if p.tlsConfig != nil {
+		config = p.tlsConfig.Clone()
+	}
+	if p.certificates != nil {
+		config.GetCertificate = p.certificates.GetCertificate
+	}
+	if p.clientCAs != nil {
+		config.ClientCAs = p.clientCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config
+}
+
+// validateServerTLS checks that the server has a certificate when client certificates are required.
+func (p *Protocol) validateServerTLS() error {
+	if p.clientCAs == nil || p.certificates != nil {
+		return nil
+	}
+	if c := p.tlsConfig; c != nil && (len(c.Certificates) > 0 || c.GetCertificate != nil || c.GetConfigForClient != nil) {
+		return nil
+	}
+	return fmt.Errorf("http client CAs option requires a server certificate, set with WithTLSConfig or WithCertificateFiles")
+}
+
+// certificateReloader loads a certificate from files, reloading it when the files are modified.
+type certificateReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+func newCertificateReloader(certFile, keyFile string) (*certificateReloader, error) {
+	r := &certificateReloader{certFile: certFile, keyFile: keyFile}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate, checking the files for changes on every handshake.
+// If the modified files can't be loaded, e.g. because they are being written, the last certificate is used.
+func (r *certificateReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, err := r.load()
+	if err != nil {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return r.cert, nil
+	}
+	return cert, nil
+}
+
+func (r *certificateReloader) load() (*tls.Certificate, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return nil, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.cert != nil && certInfo.ModTime().Equal(r.certMod) && keyInfo.ModTime().Equal(r.keyMod) {
+		return r.cert, nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading certificate %s with key %s: %w", r.certFile, r.keyFile, err)
+	}
+	r.cert, r.certMod, r.keyMod = &cert, certInfo.ModTime(), keyInfo.ModTime()
+	return r.cert, nil
+}
+
+type peerCertificateKey struct{}
+
+// withPeerCertificate adds to ctx the verified certificate of the peer, if any.
+func withPeerCertificate(ctx context.Context, state *tls.ConnectionState) context.Context {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, peerCertificateKey{}, state.VerifiedChains[0][0])
+}
+
+// PeerCertificateFromContext returns the certificate the client authenticated with,
+// verified against the CAs set with WithClientCAs, e.g. to authorize the events on its subject.
+// If the client has not been authenticated, nil is returned.
+func PeerCertificateFromContext(ctx context.Context) *x509.Certificate {
+	if cert, ok := ctx.Value(peerCertificateKey{}).(*x509.Certificate); ok {
+		return cert
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+	return &testCA{cert: cert, key: key, pool: pool}
+}
+
+// issue returns a certificate signed by the CA, PEM encoded along with its key.
+func (ca *testCA) issue(t *testing.T, serial int64, commonName string, usage x509.ExtKeyUsage) (certPEM, keyPEM []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func (ca *testCA) clientCertificate(t *testing.T, commonName string) tls.Certificate {
+	certPEM, keyPEM := ca.issue(t, 100, commonName, x509.ExtKeyUsageClientAuth)
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+	return cert
+}
+
+// writeServerCertificate writes a server certificate in dir, with the provided modification time.
+func (ca *testCA) writeServerCertificate(t *testing.T, dir string, serial int64, modTime time.Time) (certFile, keyFile string) {
+	certPEM, keyPEM := ca.issue(t, serial, "127.0.0.1", x509.ExtKeyUsageServerAuth)
+	certFile, keyFile = filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(certFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(keyFile, keyPEM, 0o600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	return certFile, keyFile
+}
+
+func TestMutualTLS(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ca := newTestCA(t)
+	certFile, keyFile := ca.writeServerCertificate(t, t.TempDir(), 2, time.Now())
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver, err := New(WithListener(listener), WithCertificateFiles(certFile, keyFile), WithClientCAs(ca.pool))
+	require.NoError(t, err)
+
+	opened := make(chan error, 1)
+	go func() {
+		opened <- receiver.OpenInbound(ctx)
+	}()
+	peers := make(chan *x509.Certificate, 1)
+	go func() {
+		for {
+			m, fn, err := receiver.Respond(ctx)
+			if err != nil {
+				return
+			}
+			peers <- PeerCertificateFromContext(m.(binding.MessageContext).Context())
+			_ = m.Finish(nil)
+			_ = fn(ctx, nil, nil)
+		}
+	}()
+
+	target := WithTarget("https://" + listener.Addr().String())
+
+	t.Run("authenticated client", func(t *testing.T) {
+		sender, err := New(target, WithTLSClientConfig(&tls.Config{
+			RootCAs:      ca.pool,
+			Certificates: []tls.Certificate{ca.clientCertificate(t, "client-a")},
+		}))
+		require.NoError(t, err)
+		err = sender.Send(ctx, test.MinMessage())
+		require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+		peer := <-peers
+		require.NotNil(t, peer)
+		require.Equal(t, "client-a", peer.Subject.CommonName)
+	})
+
+	t.Run("anonymous client", func(t *testing.T) {
+		sender, err := New(target, WithTLSClientConfig(&tls.Config{RootCAs: ca.pool}))
+		require.NoError(t, err)
+		err = sender.Send(ctx, test.MinMessage())
+		require.False(t, protocol.IsACK(err), "unexpected result %v", err)
+	})
+
+	cancel()
+	require.NoError(t, <-opened)
+}
+
+func TestCertificateFilesReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	certFile, keyFile := ca.writeServerCertificate(t, dir, 2, time.Now().Add(-time.Minute))
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver, err := New(WithListener(listener), WithCertificateFiles(certFile, keyFile))
+	require.NoError(t, err)
+	opened := make(chan error, 1)
+	go func() {
+		opened <- receiver.OpenInbound(ctx)
+	}()
+
+	servedSerial := func() int64 {
+		conn, err := tls.Dial("tcp", listener.Addr().String(), &tls.Config{RootCAs: ca.pool})
+		require.NoError(t, err)
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+	require.Equal(t, int64(2), servedSerial())
+
+	// Rotate the certificate
+	ca.writeServerCertificate(t, dir, 3, time.Now())
+	require.Equal(t, int64(3), servedSerial())
+
+	cancel()
+	require.NoError(t, <-opened)
+}
+
+func TestTLSOptions(t *testing.T) {
+	_, err := New(WithCertificateFiles("missing.crt", "missing.key"))
+	require.Error(t, err)
+	_, err = New(WithClientCAs(nil))
+	require.Error(t, err)
+	_, err = New(WithClientCAs(x509.NewCertPool()))
+	require.ErrorContains(t, err, "requires a server certificate")
+	_, err = New(WithTLSConfig(&tls.Config{}), WithClientCAs(x509.NewCertPool()))
+	require.ErrorContains(t, err, "requires a server certificate")
+	_, err = New(WithTLSConfig(&tls.Config{Certificates: []tls.Certificate{{}}}), WithClientCAs(x509.NewCertPool()))
+	require.NoError(t, err)
+	_, err = New(WithTLSConfig(nil))
+	require.Error(t, err)
+	_, err = New(WithRoundTripper(&customTransport{}), WithTLSClientConfig(&tls.Config{}))
+	require.Error(t, err)
+}
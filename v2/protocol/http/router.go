@@ -0,0 +1,112 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+)
+
+// Router serves several routes on a single server, each route being a Protocol with its own
+// receiver, rate limiter and middleware, e.g. one per tenant on "/tenants/{id}/events".
+// The values of the wildcards of the route patterns are available in the context of the
+// received events with PathParamsFromContext.
+type Router struct {
+	server *Protocol
+	mux    *http.ServeMux
+}
+
+var _ protocol.Opener = (*Router)(nil)
+
+// NewRouter returns a Router serving the routes with a server configured by opts, e.g. with
+// WithPort, WithTLSConfig or WithMiddleware to apply a middleware to all the routes.
+func NewRouter(opts ...Option) (*Router, error) {
+	server, err := New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	server.Handler = mux
+	// The routes are registered on the mux in place of the server Protocol
+	server.handlerRegistered = true
+	return &Router{server: server, mux: mux}, nil
+}
+
+// Route returns a Protocol receiving the requests matching pattern, using the syntax of
+// http.ServeMux, configured by opts, e.g. with WithRateLimiter, WithMiddleware or
+// WithOptionsHandlerFunc. The options configuring the server, like WithPort, are ignored.
+// The Protocol is served by the Router: its OpenInbound only blocks until ctx is done.
+func (r *Router) Route(pattern string, opts ...Option) (route *Protocol, err error) {
+	route, err = New(opts...)
+	if err != nil {
+		return nil, err
+	}
+	route.Path = pattern
+	route.routed = true
+
+	names := pathParamNames(pattern)
+	next := attachMiddleware(route, route.middleware)
+	handler := http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		params := make(map[string]string, len(names))
+		for _, name := range names {
+			params[name] = req.PathValue(name)
+		}
+		next.ServeHTTP(rw, req.WithContext(withPathParams(req.Context(), params)))
+	})
+
+	// http.ServeMux panics on invalid or conflicting patterns
+	defer func() {
+		if rec := recover(); rec != nil {
+			route, err = nil, fmt.Errorf("invalid route %q: %v", pattern, rec)
+		}
+	}()
+	r.mux.Handle(pattern, handler)
+	return route, nil
+}
+
+// OpenInbound serves the routes until ctx is done, see Protocol.OpenInbound.
+func (r *Router) OpenInbound(ctx context.Context) error {
+	return r.server.OpenInbound(ctx)
+}
+
+// GetListeningPort returns the listening port.
+// Returns -1 if it's not listening.
+func (r *Router) GetListeningPort() int {
+	return r.server.GetListeningPort()
+}
+
+var pathParamPattern = regexp.MustCompile(`\{([^}.]+)(\.\.\.)?\}`)
+
+// pathParamNames returns the names of the wildcards of the pattern.
+func pathParamNames(pattern string) []string {
+	var names []string
+	for _, match := range pathParamPattern.FindAllStringSubmatch(pattern, -1) {
+		if match[1] != "$" {
+			names = append(names, match[1])
+		}
+	}
+	return names
+}
+
+type pathParamsKey struct{}
+
+func withPathParams(ctx context.Context, params map[string]string) context.Context {
+	return context.WithValue(ctx, pathParamsKey{}, params)
+}
+
+// PathParamsFromContext returns the values of the wildcards of the pattern of the Router
+// route the event has been received on, by name.
+// If the event has not been received on a route, nil is returned.
+func PathParamsFromContext(ctx context.Context) map[string]string {
+	if params, ok := ctx.Value(pathParamsKey{}).(map[string]string); ok {
+		return params
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+// respondPathParams ACKs every message received on route, sending the path params of its context.
+func respondPathParams(ctx context.Context, route *Protocol) <-chan map[string]string {
+	params := make(chan map[string]string, 10)
+	go func() {
+		for {
+			m, fn, err := route.Respond(ctx)
+			if err != nil {
+				return
+			}
+			params <- PathParamsFromContext(m.(binding.MessageContext).Context())
+			_ = m.Finish(nil)
+			_ = fn(ctx, nil, nil)
+		}
+	}()
+	return params
+}
+
+func TestRouter(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	router, err := NewRouter(WithListener(listener))
+	require.NoError(t, err)
+
+	tenants, err := router.Route("/tenants/{id}/events",
+		WithMiddleware(BearerTokenAuth(NewKeyRing([]byte("token")))),
+		WithRateLimiter(NewRateLimiter(rate.Every(time.Minute), 2)),
+	)
+	require.NoError(t, err)
+	archive, err := router.Route("/archive/{path...}")
+	require.NoError(t, err)
+
+	_, err = router.Route("/archive/{path...}")
+	require.Error(t, err, "conflicting route")
+
+	opened := make(chan error, 3)
+	go func() {
+		opened <- router.OpenInbound(ctx)
+	}()
+	for _, route := range []*Protocol{tenants, archive} {
+		go func() {
+			opened <- route.OpenInbound(ctx)
+		}()
+	}
+	tenantParams := respondPathParams(ctx, tenants)
+	archiveParams := respondPathParams(ctx, archive)
+
+	target := "http://" + listener.Addr().String()
+	send := func(path string, opts ...Option) error {
+		sender, err := New(append(opts, WithTarget(target+path))...)
+		require.NoError(t, err)
+		return sender.Send(ctx, test.MinMessage())
+	}
+	authenticated := WithRequestTransformer(BearerToken(NewKeyRing([]byte("token"))))
+
+	requireStatus(t, http.StatusOK, send("/tenants/a/events", authenticated))
+	require.Equal(t, map[string]string{"id": "a"}, <-tenantParams)
+	requireStatus(t, http.StatusUnauthorized, send("/tenants/b/events"))
+	requireStatus(t, http.StatusOK, send("/tenants/b/events", authenticated))
+	require.Equal(t, map[string]string{"id": "b"}, <-tenantParams)
+	// The rate limiter of the route is exhausted
+	requireStatus(t, http.StatusTooManyRequests, send("/tenants/c/events", authenticated))
+
+	requireStatus(t, http.StatusOK, send("/archive/2021/01"))
+	require.Equal(t, map[string]string{"path": "2021/01"}, <-archiveParams)
+
+	requireStatus(t, http.StatusNotFound, send("/unknown"))
+
+	cancel()
+	for i := 0; i < 3; i++ {
+		require.NoError(t, <-opened)
+	}
+}
+
+func TestPathParamNames(t *testing.T) {
+	require.Equal(t, []string{"tenant", "id"}, pathParamNames("POST example.com/tenants/{tenant}/events/{id}"))
+	require.Equal(t, []string{"path"}, pathParamNames("/files/{path...}"))
+	require.Nil(t, pathParamNames("/{$}"))
+}
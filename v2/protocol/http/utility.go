@@ -6,12 +6,11 @@
 package http
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	nethttp "net/http"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/event"
 )
 
@@ -60,30 +59,25 @@ func NewHTTPRequestFromEvent(ctx context.Context, url string, event event.Event)
 // NewHTTPRequestFromEvents creates a http.Request object that can be used with any http.Client for sending
 // a batched set of events. This is an HTTP POST action to the provided url.
 func NewHTTPRequestFromEvents(ctx context.Context, url string, events []event.Event) (*nethttp.Request, error) {
-	// Sending batch events is quite straightforward, as there is only JSON format, so a simple implementation.
 	for _, e := range events {
 		if err := e.Validate(); err != nil {
 			return nil, err
 		}
 	}
-	var buffer bytes.Buffer
-	err := json.NewEncoder(&buffer).Encode(events)
+
+	request, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, url, nil)
 	if err != nil {
 		return nil, err
 	}
-
-	request, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodPost, url, &buffer)
-	if err != nil {
+	if err := WriteBatchRequest(ctx, events, request); err != nil {
 		return nil, err
 	}
 
-	request.Header.Set(ContentType, event.ApplicationCloudEventsBatchJSON)
-
 	return request, nil
 }
 
 // IsHTTPBatch returns if the current http.Request or http.Response is a batch event operation, by checking the
-// header `Content-Type` value.
+// header `Content-Type` value is the media type of a format.BatchFormat.
 func IsHTTPBatch(header nethttp.Header) bool {
-	return header.Get(ContentType) == event.ApplicationCloudEventsBatchJSON
+	return format.LookupBatch(header.Get(ContentType)) != nil
 }
@@ -15,6 +15,7 @@ import (
 	"testing"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/test"
 	"github.com/google/uuid"
@@ -262,6 +263,27 @@ func TestNewHTTPRequestFromEvents(t *testing.T) {
 		_, err := NewHTTPRequestFromEvents(context.Background(), ts.URL, events)
 		require.ErrorContains(t, err, "id: MUST be a non-empty string")
 	})
+
+	t.Run("xml batch format", func(t *testing.T) {
+		e := event.New()
+		e.SetID(uuid.New().String())
+		e.SetSource("example/uri")
+		e.SetType("example.type")
+		require.NoError(t, e.SetData(event.ApplicationXML, []byte("<hello>world</hello>")))
+		events := []event.Event{e}
+
+		ctx := binding.UseFormatForBatch(context.Background(), format.XMLBatch)
+		req, err := NewHTTPRequestFromEvents(ctx, ts.URL, events)
+		require.NoError(t, err)
+		require.Equal(t, event.ApplicationCloudEventsBatchXML, req.Header.Get(ContentType))
+
+		resp, err := ts.Client().Do(req)
+		require.NoError(t, err)
+
+		result, err := NewEventsFromHTTPResponse(resp)
+		require.NoError(t, err)
+		require.Equal(t, events, result)
+	})
 }
 
 func TestIsHTTPBatch(t *testing.T) {
@@ -273,4 +295,7 @@ func TestIsHTTPBatch(t *testing.T) {
 
 	header.Set(ContentType, event.ApplicationCloudEventsBatchJSON)
 	assert.True(t, IsHTTPBatch(header))
+
+	header.Set(ContentType, event.ApplicationCloudEventsBatchXML+"; charset=utf-8")
+	assert.True(t, IsHTTPBatch(header))
 }
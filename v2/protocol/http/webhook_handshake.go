@@ -0,0 +1,224 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultWebhookCallbackTimeout is the default time the sender waits for the delivery
+	// target to confirm a validation handshake through the callback.
+	DefaultWebhookCallbackTimeout = time.Minute * 10
+
+	// webhookCallbackToken is the query parameter of the callback URL identifying the handshake.
+	webhookCallbackToken = "token"
+)
+
+// WebhookHandshakeConfig configures the validation handshake run by the sender before the
+// first delivery to a target, see
+// https://github.com/cloudevents/spec/blob/v1.0/http-webhook.md#4-abuse-protection
+type WebhookHandshakeConfig struct {
+	// Origin is sent in WebHook-Request-Origin. Required.
+	Origin string
+	// RequestRate, if set, is sent in WebHook-Request-Rate, in requests per minute.
+	RequestRate *int
+	// CallbackURL, if set, is sent in WebHook-Request-Callback, so the delivery target can
+	// grant the permission asynchronously by GETting it. WebhookCallbackHandler must be
+	// served on this URL.
+	CallbackURL *url.URL
+	// CallbackTimeout is the maximum time to wait for the callback.
+	// If 0, DefaultWebhookCallbackTimeout is used.
+	CallbackTimeout time.Duration
+}
+
+// webhookTarget is the state of the validation handshake with a delivery target.
+type webhookTarget struct {
+	// done is closed once the handshake completed, successfully or not.
+	done chan struct{}
+	err  error
+	// limiter paces the requests to the target at the allowed rate.
+	limiter *rate.Limiter
+	// callback receives the headers of the callback request.
+	callback chan http.Header
+}
+
+// webhookHandshake runs the validation handshake with target if it is the first delivery to it,
+// or waits for the one in progress. Failed handshakes are run again on the next delivery.
+func (p *Protocol) webhookHandshake(ctx context.Context, target *url.URL) (*webhookTarget, error) {
+	key := target.String()
+
+	p.webhookMu.Lock()
+	t, ok := p.webhookTargets[key]
+	if !ok {
+		t = &webhookTarget{done: make(chan struct{}), callback: make(chan http.Header, 1)}
+		if p.webhookTargets == nil {
+			p.webhookTargets = make(map[string]*webhookTarget)
+		}
+		p.webhookTargets[key] = t
+	}
+	p.webhookMu.Unlock()
+
+	if !ok {
+		t.limiter, t.err = p.validateWebhookTarget(ctx, target, t)
+		if t.err != nil {
+			p.webhookMu.Lock()
+			delete(p.webhookTargets, key)
+			p.webhookMu.Unlock()
+		}
+		close(t.done)
+	}
+
+	select {
+	case <-t.done:
+		return t, t.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *Protocol) validateWebhookTarget(ctx context.Context, target *url.URL, t *webhookTarget) (*rate.Limiter, error) {
+	config := p.webhookHandshakeConfig
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("WebHook-Request-Origin", config.Origin)
+	if config.RequestRate != nil {
+		req.Header.Set("WebHook-Request-Rate", strconv.Itoa(*config.RequestRate))
+	}
+	var token string
+	if config.CallbackURL != nil {
+		token, err = p.registerWebhookCallback(t)
+		if err != nil {
+			return nil, err
+		}
+		defer p.unregisterWebhookCallback(token)
+		callback := *config.CallbackURL
+		query := callback.Query()
+		query.Set(webhookCallbackToken, token)
+		callback.RawQuery = query.Encode()
+		req.Header.Set("WebHook-Request-Callback", callback.String())
+	}
+
+	if err := p.transformRequest(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webhook validation handshake with %s: %w", target, err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("webhook validation handshake with %s: unexpected status %d", target, resp.StatusCode)
+	}
+
+	headers := resp.Header
+	if headers.Get("WebHook-Allowed-Origin") == "" && token != "" {
+		timeout := config.CallbackTimeout
+		if timeout == 0 {
+			timeout = DefaultWebhookCallbackTimeout
+		}
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		select {
+		case headers = <-t.callback:
+		case <-timer.C:
+			return nil, fmt.Errorf("webhook validation handshake with %s: callback not received within %s", target, timeout)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return config.allowedRate(target, headers)
+}
+
+// allowedRate checks the delivery target granted the permission in headers, returning
+// a limiter honouring the allowed rate.
+func (config *WebhookHandshakeConfig) allowedRate(target *url.URL, headers http.Header) (*rate.Limiter, error) {
+	if origin := headers.Get("WebHook-Allowed-Origin"); origin != "*" && origin != config.Origin {
+		return nil, fmt.Errorf("webhook validation handshake with %s: origin %q not allowed", target, config.Origin)
+	}
+	allowed := strings.TrimSpace(headers.Get("WebHook-Allowed-Rate"))
+	if allowed == "" || allowed == "*" {
+		return rate.NewLimiter(rate.Inf, 1), nil
+	}
+	perMinute, err := strconv.Atoi(allowed)
+	if err != nil || perMinute <= 0 {
+		return nil, fmt.Errorf("webhook validation handshake with %s: invalid allowed rate %q", target, allowed)
+	}
+	return rate.NewLimiter(rate.Limit(float64(perMinute)/60), 1), nil
+}
+
+func (p *Protocol) registerWebhookCallback(t *webhookTarget) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+
+	p.webhookMu.Lock()
+	defer p.webhookMu.Unlock()
+	if p.webhookCallbacks == nil {
+		p.webhookCallbacks = make(map[string]*webhookTarget)
+	}
+	p.webhookCallbacks[token] = t
+	return token, nil
+}
+
+func (p *Protocol) unregisterWebhookCallback(token string) {
+	p.webhookMu.Lock()
+	defer p.webhookMu.Unlock()
+	delete(p.webhookCallbacks, token)
+}
+
+// WebhookCallbackHandler handles the requests of the delivery targets confirming
+// a validation handshake on WebhookHandshakeConfig.CallbackURL.
+func (p *Protocol) WebhookCallbackHandler(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet && req.Method != http.MethodPost {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	p.webhookMu.Lock()
+	t, ok := p.webhookCallbacks[req.URL.Query().Get(webhookCallbackToken)]
+	p.webhookMu.Unlock()
+	if !ok {
+		rw.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	select {
+	case t.callback <- req.Header.Clone():
+	default:
+		// Already confirmed
+	}
+	rw.WriteHeader(http.StatusOK)
+}
+
+// waitWebhookRate blocks until the request can be sent at the rate allowed by the delivery target.
+func (p *Protocol) waitWebhookRate(req *http.Request) error {
+	if p.webhookHandshakeConfig == nil {
+		return nil
+	}
+	t, err := p.webhookHandshake(req.Context(), req.URL)
+	if err != nil {
+		return err
+	}
+	return t.limiter.Wait(req.Context())
+}
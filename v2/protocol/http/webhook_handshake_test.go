@@ -0,0 +1,201 @@
+/*
+ Copyright 2021 The CloudEvents Authors
+ SPDX-License-Identifier: Apache-2.0
+*/
+
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/test"
+)
+
+const testOrigin = "http://sender.example.com"
+
+// testWebhookTarget is a delivery target recording the requests, answering OPTIONS with options.
+type testWebhookTarget struct {
+	*httptest.Server
+	mu       sync.Mutex
+	methods  []string
+	requests []*http.Request
+}
+
+func newTestWebhookTarget(t *testing.T, options http.HandlerFunc) *testWebhookTarget {
+	target := &testWebhookTarget{}
+	target.Server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		target.mu.Lock()
+		target.methods = append(target.methods, req.Method)
+		target.requests = append(target.requests, req)
+		target.mu.Unlock()
+		if req.Method == http.MethodOptions {
+			options(rw, req)
+		}
+	}))
+	t.Cleanup(target.Close)
+	return target
+}
+
+func (target *testWebhookTarget) Methods() []string {
+	target.mu.Lock()
+	defer target.mu.Unlock()
+	return append([]string(nil), target.methods...)
+}
+
+func TestWebhookHandshake(t *testing.T) {
+	ctx := context.Background()
+	rate := 600
+
+	target := newTestWebhookTarget(t, func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("WebHook-Allowed-Origin", req.Header.Get("WebHook-Request-Origin"))
+		rw.Header().Set("WebHook-Allowed-Rate", "600")
+	})
+	sender, err := New(WithTarget(target.URL), WithWebhookHandshake(WebhookHandshakeConfig{Origin: testOrigin, RequestRate: &rate}))
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		err = sender.Send(ctx, test.MinMessage())
+		require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+	}
+	// 600 requests per minute are a request every 100ms
+	require.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+
+	require.Equal(t, []string{http.MethodOptions, http.MethodPost, http.MethodPost, http.MethodPost}, target.Methods())
+	options := target.requests[0]
+	require.Equal(t, testOrigin, options.Header.Get("WebHook-Request-Origin"))
+	require.Equal(t, "600", options.Header.Get("WebHook-Request-Rate"))
+	require.Empty(t, options.Header.Get("WebHook-Request-Callback"))
+}
+
+func TestWebhookHandshakeRefused(t *testing.T) {
+	ctx := context.Background()
+
+	for name, options := range map[string]http.HandlerFunc{
+		"method not allowed": func(rw http.ResponseWriter, req *http.Request) {
+			rw.WriteHeader(http.StatusMethodNotAllowed)
+		},
+		"other origin": func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("WebHook-Allowed-Origin", "http://other.example.com")
+		},
+		"invalid rate": func(rw http.ResponseWriter, req *http.Request) {
+			rw.Header().Set("WebHook-Allowed-Origin", "*")
+			rw.Header().Set("WebHook-Allowed-Rate", "none")
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			target := newTestWebhookTarget(t, options)
+			sender, err := New(WithTarget(target.URL), WithWebhookHandshake(WebhookHandshakeConfig{Origin: testOrigin}))
+			require.NoError(t, err)
+
+			err = sender.Send(ctx, test.MinMessage())
+			require.False(t, protocol.IsACK(err), "unexpected result %v", err)
+			// The handshake is attempted again
+			err = sender.Send(ctx, test.MinMessage())
+			require.False(t, protocol.IsACK(err), "unexpected result %v", err)
+			require.Equal(t, []string{http.MethodOptions, http.MethodOptions}, target.Methods())
+		})
+	}
+}
+
+// openWebhookReceiver opens a receiver with the default OPTIONS handler, returning its URL
+// and the callbacks it received.
+func openWebhookReceiver(t *testing.T, ctx context.Context, autoACKCallback bool) (*Protocol, string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	receiver, err := New(
+		WithListener(listener),
+		WithDefaultOptionsHandlerFunc([]string{http.MethodPost}, 6000, []string{testOrigin}, autoACKCallback),
+	)
+	require.NoError(t, err)
+	callbacks := make(chan string, 1)
+	receiver.middleware = append(receiver.middleware, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if cb := req.Header.Get("WebHook-Request-Callback"); cb != "" {
+				callbacks <- cb
+			}
+			next.ServeHTTP(rw, req)
+		})
+	})
+	_, done := openAndRespond(t, ctx, receiver)
+	t.Cleanup(func() {
+		require.NoError(t, <-done)
+	})
+	return receiver, "http://" + listener.Addr().String(), callbacks
+}
+
+// newCallbackSender returns a sender serving the callbacks of the validation handshake.
+func newCallbackSender(t *testing.T, target string, timeout time.Duration) *Protocol {
+	var sender *Protocol
+	callbackServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		sender.WebhookCallbackHandler(rw, req)
+	}))
+	t.Cleanup(callbackServer.Close)
+	callbackURL, err := url.Parse(callbackServer.URL + "/callback")
+	require.NoError(t, err)
+
+	sender, err = New(WithTarget(target), WithWebhookHandshake(WebhookHandshakeConfig{
+		Origin:          testOrigin,
+		CallbackURL:     callbackURL,
+		CallbackTimeout: timeout,
+	}))
+	require.NoError(t, err)
+	return sender
+}
+
+func TestWebhookHandshakeCallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, target, callbacks := openWebhookReceiver(t, ctx, true)
+	sender := newCallbackSender(t, target, time.Minute)
+
+	err := sender.Send(ctx, test.MinMessage())
+	require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+	require.Contains(t, <-callbacks, "/callback?token=")
+	cancel()
+}
+
+func TestWebhookHandshakeManualCallback(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	receiver, target, callbacks := openWebhookReceiver(t, ctx, false)
+
+	t.Run("confirmed", func(t *testing.T) {
+		sender := newCallbackSender(t, target, time.Minute)
+		confirmed := make(chan error, 1)
+		go func() {
+			confirmed <- receiver.ConfirmWebhookCallback(ctx, <-callbacks, testOrigin)
+		}()
+		err := sender.Send(ctx, test.MinMessage())
+		require.True(t, protocol.IsACK(err), "unexpected result %v", err)
+		require.NoError(t, <-confirmed)
+	})
+
+	t.Run("not confirmed", func(t *testing.T) {
+		sender := newCallbackSender(t, target, 50*time.Millisecond)
+		err := sender.Send(ctx, test.MinMessage())
+		require.False(t, protocol.IsACK(err), "unexpected result %v", err)
+		require.ErrorContains(t, err, "callback not received")
+		<-callbacks
+	})
+
+	require.Error(t, receiver.ConfirmWebhookCallback(ctx, target+"/unknown", testOrigin))
+	cancel()
+}
+
+func TestWithWebhookHandshake(t *testing.T) {
+	_, err := New(WithWebhookHandshake(WebhookHandshakeConfig{}))
+	require.Error(t, err)
+}
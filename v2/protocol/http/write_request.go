@@ -15,6 +15,7 @@ import (
 	"github.com/cloudevents/sdk-go/v2/binding"
 	"github.com/cloudevents/sdk-go/v2/binding/format"
 	"github.com/cloudevents/sdk-go/v2/binding/spec"
+	"github.com/cloudevents/sdk-go/v2/event"
 	"github.com/cloudevents/sdk-go/v2/types"
 )
 
@@ -34,11 +35,32 @@ func WriteRequest(ctx context.Context, m binding.Message, httpRequest *http.Requ
 	return err
 }
 
+// WriteBatchRequest fills the provided httpRequest with the events, using the format configured with
+// binding.UseFormatForBatch, "application/cloudevents-batch+json" by default.
+func WriteBatchRequest(ctx context.Context, events []event.Event, httpRequest *http.Request) error {
+	f := binding.GetBatchFormat(ctx)
+	b, err := f.MarshalBatch(events)
+	if err != nil {
+		return err
+	}
+	if httpRequest.Header == nil {
+		httpRequest.Header = http.Header{}
+	}
+	httpRequest.Header.Set(ContentType, f.MediaType())
+	if err := (*httpRequestWriter)(httpRequest).setBody(bytes.NewReader(b)); err != nil {
+		return err
+	}
+	return (*httpRequestWriter)(httpRequest).compressBody(ctx)
+}
+
 type httpRequestWriter http.Request
 
 func (b *httpRequestWriter) SetStructuredEvent(ctx context.Context, format format.Format, event io.Reader) error {
 	b.Header.Set(ContentType, format.MediaType())
-	return b.setBody(event)
+	if err := b.setBody(event); err != nil {
+		return err
+	}
+	return b.compressBody(ctx)
 }
 
 func (b *httpRequestWriter) Start(ctx context.Context) error {
@@ -46,7 +68,28 @@ func (b *httpRequestWriter) Start(ctx context.Context) error {
 }
 
 func (b *httpRequestWriter) End(ctx context.Context) error {
-	return nil
+	return b.compressBody(ctx)
+}
+
+// compressBody compresses the body when configured with WithCompressionAtContext.
+func (b *httpRequestWriter) compressBody(ctx context.Context) error {
+	c := compressionFrom(ctx)
+	if c == nil || b.Body == nil || b.Body == http.NoBody || isContentEncoded(b.Header) {
+		return nil
+	}
+	size := int64(-1)
+	if sized, ok := b.Body.(*sizedBody); ok {
+		size = sized.size
+	} else if b.GetBody != nil {
+		size = b.ContentLength
+	}
+	body, compressed, err := c.compress(b.Body, size, b.GetBody != nil)
+	if err != nil || !compressed {
+		return err
+	}
+	b.Header.Set(ContentEncoding, c.encoding)
+	b.ContentLength, b.GetBody = 0, nil
+	return b.setBody(body)
 }
 
 func (b *httpRequestWriter) SetData(data io.Reader) error {
@@ -83,6 +126,12 @@ func (b *httpRequestWriter) setBody(body io.Reader) error {
 				r := snapshot
 				return io.NopCloser(&r), nil
 			}
+		case *sizedBody:
+			// The body is piped from another HTTP message
+			b.ContentLength = v.size
+			if v.size == 0 {
+				b.Body = http.NoBody
+			}
 		default:
 			// This is where we'd set it to -1 (at least
 			// if body != NoBody) to mean unknown, but
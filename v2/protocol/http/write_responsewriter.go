@@ -46,7 +46,7 @@ type httpResponseWriter struct {
 func (b *httpResponseWriter) SetStructuredEvent(ctx context.Context, format format.Format, event io.Reader) error {
 	b.rw.Header().Set(ContentType, format.MediaType())
 	b.body = event
-	return b.finalizeWriter()
+	return b.finalizeWriter(ctx)
 }
 
 func (b *httpResponseWriter) Start(ctx context.Context) error {
@@ -86,10 +86,11 @@ func (b *httpResponseWriter) SetData(reader io.Reader) error {
 	return nil
 }
 
-func (b *httpResponseWriter) finalizeWriter() error {
+func (b *httpResponseWriter) finalizeWriter(ctx context.Context) error {
 	if b.body != nil {
 		// Try to figure it out if we have a content-length
 		contentLength := -1
+		buffered := true
 		switch v := b.body.(type) {
 		case *bytes.Buffer:
 			contentLength = v.Len()
@@ -97,6 +98,24 @@ func (b *httpResponseWriter) finalizeWriter() error {
 			contentLength = v.Len()
 		case *strings.Reader:
 			contentLength = v.Len()
+		case *sizedBody:
+			contentLength = int(v.size)
+			buffered = false
+		}
+
+		if c := compressionFrom(ctx); c != nil && !isContentEncoded(b.rw.Header()) {
+			body, compressed, err := c.compress(b.body, int64(contentLength), buffered && contentLength != -1)
+			if err != nil {
+				return err
+			}
+			if compressed {
+				b.rw.Header().Set(ContentEncoding, c.encoding)
+				b.body = body
+				contentLength = -1
+				if buf, ok := body.(*bytes.Buffer); ok {
+					contentLength = buf.Len()
+				}
+			}
 		}
 
 		if contentLength != -1 {
@@ -119,7 +138,7 @@ func (b *httpResponseWriter) finalizeWriter() error {
 }
 
 func (b *httpResponseWriter) End(ctx context.Context) error {
-	return b.finalizeWriter()
+	return b.finalizeWriter(ctx)
 }
 
 var _ binding.StructuredWriter = (*httpResponseWriter)(nil) // Test it conforms to the interface
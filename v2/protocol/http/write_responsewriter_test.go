@@ -82,7 +82,7 @@ func TestWriteHttpResponseWriter(t *testing.T) {
 				return NewMessageFromHttpRequest(req)
 			},
 			expectedEncoding:    binding.EncodingStructured,
-			expectContentLength: false,
+			expectContentLength: true,
 		},
 		{
 			name:    "Binary to binary HttpRequest to Binary",
@@ -94,7 +94,7 @@ func TestWriteHttpResponseWriter(t *testing.T) {
 				return NewMessageFromHttpRequest(req)
 			},
 			expectedEncoding:    binding.EncodingBinary,
-			expectContentLength: false,
+			expectContentLength: true,
 		},
 		{
 			name:                "Event to Structured",
@@ -16,6 +16,14 @@ type Opener interface {
 	OpenInbound(ctx context.Context) error
 }
 
+// InboundStopper is an optional interface an Opener can implement to stop accepting new inbound
+// messages before the ctx of OpenInbound is done, e.g. while the messages already received are drained.
+type InboundStopper interface {
+	// StopInbound makes OpenInbound stop accepting new messages and return once the messages
+	// already accepted have been responded. It doesn't wait for OpenInbound to return.
+	StopInbound(ctx context.Context) error
+}
+
 // Closer is the common interface for things that can be closed.
 // After invoking Close(ctx), you cannot reuse the object you closed.
 type Closer interface {
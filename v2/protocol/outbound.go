@@ -9,6 +9,7 @@ import (
 	"context"
 
 	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/cloudevents/sdk-go/v2/event"
 )
 
 // Sender sends messages.
@@ -33,6 +34,46 @@ type SendCloser interface {
 	Closer
 }
 
+// BatchSender sends a batch of events in a single message.
+//
+// Optional interface that may be implemented by protocols that support
+// batching, e.g. using the "application/cloudevents-batch+json" format.
+type BatchSender interface {
+	// SendBatch sends the events in a single message, returning the result
+	// of the whole batch. When the recipient reports the result of each event,
+	// the returned result is a *BatchResult.
+	SendBatch(ctx context.Context, events []event.Event) error
+}
+
+// BatchResult is the result of a batch for which the recipient reported the result of each event.
+// It wraps the result of the whole batch, which isn't an ACK unless every event has been acknowledged.
+type BatchResult struct {
+	// Result of the whole batch.
+	Result Result
+	// Results of the events, in the order of the batch.
+	Results []Result
+}
+
+// Error implements error.
+func (r *BatchResult) Error() string {
+	return r.Result.Error()
+}
+
+// Unwrap returns the result of the whole batch.
+func (r *BatchResult) Unwrap() error {
+	return r.Result
+}
+
+// BatchEventResult returns the result of the i-th event of a batch sent with the result r:
+// the result of the event if r is a *BatchResult, otherwise r itself.
+func BatchEventResult(r Result, i int) Result {
+	var br *BatchResult
+	if ResultAs(r, &br) && i < len(br.Results) {
+		return br.Results[i]
+	}
+	return r
+}
+
 // Requester sends a message and receives a response
 //
 // Optional interface that may be implemented by protocols that support